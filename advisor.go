@@ -0,0 +1,131 @@
+package main
+
+import "math"
+
+// Advice is the advisor's pick for the next purchase
+type Advice struct {
+	Category int
+	Index    int
+	Item     Item
+	Reason   string // short payoff summary shown in the shop
+}
+
+// gunDPS is the damage per second a gun deals
+func gunDPS(damage int, attackSpeed float64) float64 {
+	return float64(damage) * attackSpeed
+}
+
+// coinsPerDiamond is what a diamond is worth in coins at the current
+// coin:diamond income ratio. Without diamond income it is valued as if a
+// first Playbox made one a second.
+func coinsPerDiamond(s Snapshot) float64 {
+	return s.State.coinsPerS / math.Max(s.State.diamPerS, 1)
+}
+
+// coinValue is an item's price in coins, diamonds converted
+func coinValue(s Snapshot, item Item) float64 {
+	return float64(item.costCoins) + float64(item.costDiamonds)*coinsPerDiamond(s)
+}
+
+// GetAdvice recommends the next purchase. While the hunter is winning the
+// fight it picks the gun or Door level that adds the most damage dealt
+// before the door breaks per coin; otherwise it picks the producer (Bed or
+// Playbox) that pays for itself soonest, diamonds valued in coins.
+func GetAdvice(s Snapshot) (Advice, bool) {
+	dps := 0.0
+	for _, gun := range s.State.guns {
+		dps += gunDPS(gun.damage, gun.attackSpeed)
+	}
+
+	hunterHP := s.State.hunterHP
+	hunterAttack := s.State.hunterAttack
+	if !s.State.hunterActive {
+		hunterHP = GetHunterHP(s.State.hunterLevel)
+		hunterAttack = GetHunterAttack(s.State.hunterLevel)
+	}
+	holds := func(doorHP int) float64 {
+		if hunterAttack <= 0 {
+			return math.Inf(1)
+		}
+		return math.Ceil(float64(doorHP)/float64(hunterAttack)) * difficulty.HunterAttackInterval.Seconds()
+	}
+	doorHolds := holds(s.State.doorHP)
+	killTime := func(dps float64) float64 {
+		if dps <= 0 {
+			return math.Inf(1)
+		}
+		return float64(hunterHP) / dps
+	}
+
+	// Defense first: the hunter breaks the door before the guns kill it.
+	// A gun deals its DPS for as long as the door holds; a Door level
+	// (bought at full HP) lets the guns there are fire for longer.
+	if killTime(dps) >= doorHolds && hunterAttack > 0 {
+		best, found := Advice{}, false
+		bestValue := 0.0
+		consider := func(category, i int, item Item, damage float64, reason string) {
+			if value := damage / coinValue(s, item); !found || value > bestValue {
+				best = Advice{Category: category, Index: i, Item: item, Reason: reason}
+				bestValue, found = value, true
+			}
+		}
+		for i, item := range s.Shop[2] {
+			if !s.CanAfford(item) {
+				continue
+			}
+			gain := gunDPS(item.damage, item.attackSpeed)
+			consider(2, i, item, gain*doorHolds,
+				T("advice.gun", FormatDecimal(gain, 0), FormatDecimal(killTime(dps+gain), 0), FormatDecimal(doorHolds, 0)))
+		}
+		for i, item := range s.Shop[0] {
+			if item.itemType != "door" || !s.CanAfford(item) || dps <= 0 {
+				continue
+			}
+			newHolds := holds(GetDoorHP(item.currentLevel + 1))
+			consider(0, i, item, dps*(newHolds-doorHolds),
+				T("advice.door", FormatDecimal(newHolds-doorHolds, 0), FormatDecimal(newHolds, 0), FormatDecimal(killTime(dps), 0)))
+		}
+		if found {
+			return best, true
+		}
+	}
+
+	// Economy: shortest payback time among affordable producers
+	best, found := Advice{}, false
+	bestPayback := 0.0
+	for category, items := range s.Shop {
+		for i, item := range items {
+			if !s.CanAfford(item) {
+				continue
+			}
+			// Payback is in seconds: the price in coins over the gain
+			// in coins per second
+			var gain, coinGain float64
+			var unit string
+			switch item.itemType {
+			case "bed":
+				gain, unit = item.production-s.State.coinsPerS, T("unit.coins_per_s")
+				coinGain = gain
+			case "playbox":
+				gain, unit = item.production-s.State.diamPerS, T("unit.diamonds_per_s")
+				coinGain = gain * coinsPerDiamond(s)
+			default:
+				continue
+			}
+			if coinGain <= 0 {
+				continue
+			}
+			payback := coinValue(s, item) / coinGain
+			if !found || payback < bestPayback {
+				best = Advice{
+					Category: category,
+					Index:    i,
+					Item:     item,
+					Reason:   T("advice.producer", FormatNumber(gain), unit, FormatDecimal(payback, 0)),
+				}
+				bestPayback, found = payback, true
+			}
+		}
+	}
+	return best, found
+}
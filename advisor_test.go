@@ -0,0 +1,50 @@
+package main
+
+import "testing"
+
+func TestGetAdviceDefense(t *testing.T) {
+	SetDifficulty(difficultyPresets["normal"])
+	InitGameWithSeed(1)
+	gameState.guns = []Gun{{name: "Pistol", damage: 30, attackSpeed: 1}}
+	gameState.hunterActive = true
+	gameState.hunterAttack = GetHunterAttack(1)
+
+	tests := []struct {
+		name     string
+		hunterHP int
+		doorHP   int
+		want     string
+	}{
+		// A battered door falls long before the guns win: another level
+		// at full HP buys more damage than a second Pistol
+		{"door about to break", GetHunterHP(1), 1, "Door"},
+		// With the door healthy but the guns far too slow, a gun helps most
+		{"guns too slow", 100 * GetHunterHP(1), GetDoorHP(1), "Pistol"},
+	}
+	for _, tt := range tests {
+		gameState.hunterHP = tt.hunterHP
+		gameState.doorHP = tt.doorHP
+		gameState.coins = 16
+		advice, ok := GetAdvice(TakeSnapshot())
+		if !ok || advice.Item.name != tt.want {
+			t.Errorf("%s: advised %q (%v), want %s", tt.name, advice.Item.name, ok, tt.want)
+		}
+	}
+}
+
+func TestGetAdvicePlayboxPayback(t *testing.T) {
+	SetDifficulty(difficultyPresets["normal"])
+	InitGameWithSeed(1)
+	gameState.guns = []Gun{{name: "Sniper", damage: 1000, attackSpeed: 1}}
+	gameState.coins = 1000
+	gameState.bedLevel = 6
+	gameState.coinsPerS = 32
+
+	// Every Bed pays back in 25s: the next one costs 800 coins for 32
+	// more coins/s. The first Playbox costs 200 coins for a diamond a
+	// second, worth 32 coins at this income: paid back in about 6s.
+	advice, ok := GetAdvice(TakeSnapshot())
+	if !ok || advice.Item.name != "Playbox" {
+		t.Fatalf("advised %q (%v), want Playbox", advice.Item.name, ok)
+	}
+}
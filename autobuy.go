@@ -0,0 +1,168 @@
+package main
+
+import "github.com/rivo/tview"
+
+// AutoBuyer buys one kind of shop item on its own once unlocked
+type AutoBuyer struct {
+	kind     string // bed, door, playbox or defense
+	owned    bool
+	enabled  bool
+	reserve  int  // percent of coins and diamonds never spent
+	idleOnly bool // only buy while no hunter is attacking
+}
+
+// autoBuyerKind describes what an auto-buyer buys and what unlocking it costs
+type autoBuyerKind struct {
+	kind     string
+	name     string // shop name, e.g. "Auto Bed"
+	category int    // shop category of the item it buys
+	item     string // shop item it buys
+	coins    int
+	diamonds int
+}
+
+// autoBuyerKinds are the auto-buyers in shop and Your Items order
+var autoBuyerKinds = []autoBuyerKind{
+	{kind: "bed", name: "Auto Bed", category: 0, item: "Bed", coins: 300, diamonds: 20},
+	{kind: "door", name: "Auto Door", category: 0, item: "Door", coins: 300, diamonds: 20},
+	{kind: "playbox", name: "Auto Playbox", category: 1, item: "Playbox", coins: 600, diamonds: 40},
+	{kind: "defense", name: "Auto Defense", category: 1, item: "Guard", coins: 200, diamonds: 30},
+}
+
+// autoBuyerCategory is the shop category auto-buyers are unlocked in
+const autoBuyerCategory = 3
+
+// reserveSteps are the reserve rules an auto-buyer cycles through
+var reserveSteps = []int{0, 20, 50}
+
+// newAutoBuyers returns one locked auto-buyer per kind
+func newAutoBuyers() []AutoBuyer {
+	buyers := []AutoBuyer{}
+	for _, k := range autoBuyerKinds {
+		buyers = append(buyers, AutoBuyer{kind: k.kind})
+	}
+	return buyers
+}
+
+// autoBuyerItems are the shop offerings of the auto-buyer category
+func autoBuyerItems() []Item {
+	items := []Item{}
+	for i, k := range autoBuyerKinds {
+		if gameState.autoBuyers[i].owned {
+			continue
+		}
+		items = append(items, Item{
+			name:         k.name,
+			currentLevel: 0,
+			maxLevel:     1,
+			costCoins:    scalePrice(k.coins),
+			costDiamonds: scalePrice(k.diamonds),
+			description:  T("desc.autobuyer", itemName(k.item)),
+			itemType:     "autobuyer",
+		})
+	}
+	return items
+}
+
+// unlockAutoBuyer turns on the auto-buyer sold as name
+func unlockAutoBuyer(name string) {
+	for i, k := range autoBuyerKinds {
+		if k.name == name {
+			gameState.autoBuyers[i].owned = true
+			gameState.autoBuyers[i].enabled = true
+		}
+	}
+}
+
+// RunAutoBuyers lets every enabled auto-buyer buy at most one item. It
+// runs once per game second from StepGame.
+func RunAutoBuyers(logPanel *tview.TextView) {
+	bought := false
+	for i, buyer := range gameState.autoBuyers {
+		if !buyer.owned || !buyer.enabled || buyer.idleOnly && gameState.hunterActive {
+			continue
+		}
+		k := autoBuyerKinds[i]
+		for _, item := range GetAvailableItemsByCategory(k.category) {
+			if item.name != k.item {
+				continue
+			}
+			// Whatever it pays, the reserve share of each resource stays put
+			keep := BigNum(100-buyer.reserve) / 100
+			if item.costCoins > gameState.coins*keep || item.costDiamonds > gameState.diamonds*keep {
+				break
+			}
+			msg := purchaseItem(item)
+			AddLog(logPanel, LogEconomy, "[gray]"+T("log.autobuyer", itemName(k.name))+"[white] "+msg)
+			bought = true
+			break
+		}
+	}
+	if bought {
+		updateItemsPanelList()
+	}
+}
+
+// autoBuyerRow finds the auto-buyer shown on row of the Your Items panel.
+// Auto-buyers are listed last, after the guns.
+func autoBuyerRow(row int) (int, bool) {
+	first := len(gameState.itemsPanelItems)
+	for _, buyer := range gameState.autoBuyers {
+		if buyer.owned {
+			first--
+		}
+	}
+	for i, buyer := range gameState.autoBuyers {
+		if !buyer.owned {
+			continue
+		}
+		if row == first {
+			return i, true
+		}
+		first++
+	}
+	return 0, false
+}
+
+// changeAutoBuyer applies an auto-buyer action to buyer i and reports
+// whether anything changed
+func changeAutoBuyer(kind ActionKind, i int, logPanel *tview.TextView) bool {
+	if i < 0 || i >= len(gameState.autoBuyers) || !gameState.autoBuyers[i].owned {
+		return false
+	}
+	buyer := &gameState.autoBuyers[i]
+	switch kind {
+	case ActionAutoBuyerToggle:
+		buyer.enabled = !buyer.enabled
+	case ActionAutoBuyerReserve:
+		next := 0
+		for j, r := range reserveSteps {
+			if r == buyer.reserve {
+				next = (j + 1) % len(reserveSteps)
+			}
+		}
+		buyer.reserve = reserveSteps[next]
+	case ActionAutoBuyerIdle:
+		buyer.idleOnly = !buyer.idleOnly
+	default:
+		return false
+	}
+	AddLog(logPanel, LogEconomy, "[cyan]"+describeAutoBuyer(i)+"[white]")
+	updateItemsPanelList()
+	return true
+}
+
+// describeAutoBuyer is the Your Items line of auto-buyer i, e.g.
+// "Auto Bed: on, reserve 20%, only between hunters"
+func describeAutoBuyer(i int) string {
+	buyer := gameState.autoBuyers[i]
+	state := T("autobuyer.off")
+	if buyer.enabled {
+		state = T("autobuyer.on")
+	}
+	line := T("owned.autobuyer", itemName(autoBuyerKinds[i].name), state, buyer.reserve)
+	if buyer.idleOnly {
+		line += ", " + T("autobuyer.idle_only")
+	}
+	return line
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// BigNum is a resource amount. It keeps fractions so slow producers still
+// accumulate, and as a float64 it scales far past the int range.
+type BigNum float64
+
+// String formats the whole part of n, e.g. 999, 1.5K, 12.3M, 4.56e+15
+func (n BigNum) String() string {
+	return FormatNumber(math.Floor(float64(n)))
+}
+
+// FormatNumber formats v with the locale's suffixes (K/M/B/T in English),
+// falling back to scientific notation past the last suffix. Small values
+// keep one decimal if they have a fractional part.
+func FormatNumber(v float64) string {
+	abs := math.Abs(v)
+	if abs < 1000 {
+		if v == math.Trunc(v) {
+			return fmt.Sprintf("%.0f", v)
+		}
+		return localizeDecimal(fmt.Sprintf("%.1f", v))
+	}
+
+	exp := int(math.Log10(abs)) / 3
+	if abs/math.Pow(1000, float64(exp)) >= 999.995 {
+		// Would round up to 1000, e.g. 999999 is 1M rather than 1000K
+		exp++
+	}
+	if exp >= len(locale.Suffixes) {
+		return localizeDecimal(fmt.Sprintf("%.2e", v))
+	}
+	scaled := v / math.Pow(1000, float64(exp))
+	s := strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.2f", scaled), "0"), ".")
+	return localizeDecimal(s) + locale.Suffixes[exp]
+}
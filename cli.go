@@ -0,0 +1,531 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Exit codes shared by every subcommand
+const (
+	exitOK    = 0
+	exitError = 1 // the command ran but failed
+	exitUsage = 2 // bad arguments
+)
+
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) int
+}
+
+// commands lists the subcommands in the order shown by --help
+var commands []command
+
+func init() {
+	commands = []command{
+		{"play", "play in the terminal (the default)", RunPlay},
+		{"sim", "run headless games with a built-in strategy", RunSim},
+		{"optimize", "search for the fastest winning build order", RunOptimize},
+		{"join", "play a dreamer in a game hosted with play --host", RunJoin},
+		{"serve", "host the game over SSH, one game per connection", RunServe},
+		{"replay", "re-run a recorded game and print its timeline", RunReplay},
+		{"stats", "show the results recorded in a save slot", RunStats},
+		{"config", "show or change the config file", RunConfig},
+	}
+}
+
+// RunCLI dispatches args (without the program name) to a subcommand and
+// returns the process exit code
+func RunCLI(args []string) int {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") && !isHelpArg(args[0]) {
+		return RunPlay(args)
+	}
+	if isHelpArg(args[0]) || args[0] == "help" {
+		printUsage(os.Stdout)
+		return exitOK
+	}
+	for _, c := range commands {
+		if c.name == args[0] {
+			return c.run(args[1:])
+		}
+	}
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n", args[0])
+	printUsage(os.Stderr)
+	return exitUsage
+}
+
+func isHelpArg(arg string) bool {
+	return arg == "-h" || arg == "-help" || arg == "--help"
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: %s [command] [flags]\n\nCommands:\n", programName())
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintf(w, "\nRun '%s <command> --help' for the flags of a command.\n", programName())
+	fmt.Fprintf(w, "Exit codes: %d success, %d failure, %d usage error.\n", exitOK, exitError, exitUsage)
+}
+
+func programName() string {
+	if len(os.Args) > 0 && os.Args[0] != "" {
+		name := os.Args[0]
+		if i := strings.LastIndexAny(name, `/\`); i >= 0 {
+			name = name[i+1:]
+		}
+		return name
+	}
+	return "haunted-dorm"
+}
+
+// parseFlags parses args and maps failures to an exit code. ok is false
+// when the caller should return code right away.
+func parseFlags(fs *flag.FlagSet, args []string) (code int, ok bool) {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK, false
+		}
+		return exitUsage, false
+	}
+	return exitOK, true
+}
+
+// configOptions are the --config and --slot flags and the loaded config
+type configOptions struct {
+	configPath string
+	config     Config
+	slot       string
+	loadErr    error
+}
+
+// addConfigFlags registers --config and --slot on fs. The config file is
+// loaded up front so its values become the defaults of later flags.
+func addConfigFlags(fs *flag.FlagSet, args []string) *configOptions {
+	opts := &configOptions{configPath: DefaultConfigPath()}
+	if path := findFlagValue(args, "config"); path != "" {
+		opts.configPath = path
+	}
+	opts.config, opts.loadErr = LoadConfig(opts.configPath)
+
+	fs.StringVar(&opts.configPath, "config", opts.configPath, "config file path")
+	fs.StringVar(&opts.slot, "slot", opts.config.Slot, "save slot name")
+	return opts
+}
+
+// check reports config loading or slot errors after parsing
+func (o *configOptions) check(name string) (int, bool) {
+	if o.loadErr != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", name, o.loadErr)
+		return exitError, false
+	}
+	if err := validateSlot(o.slot); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+		return exitUsage, false
+	}
+	return exitOK, true
+}
+
+func (o *configOptions) slotDir() string {
+	return slotDir(o.configPath, o.slot)
+}
+
+// findFlagValue returns the value of -name or --name in args, if given
+func findFlagValue(args []string, name string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		for _, prefix := range []string{"-" + name, "--" + name} {
+			if arg == prefix && i+1 < len(args) {
+				return args[i+1]
+			}
+			if strings.HasPrefix(arg, prefix+"=") {
+				return strings.TrimPrefix(arg, prefix+"=")
+			}
+		}
+	}
+	return ""
+}
+
+// RunPlay implements the play subcommand: the interactive TUI
+func RunPlay(args []string) int {
+	fs := flag.NewFlagSet("play", flag.ContinueOnError)
+	opts := addConfigFlags(fs, args)
+	seed := fs.Int64("seed", 0, "seed for a new game (0 picks one at random)")
+	difficultyName := fs.String("difficulty", opts.config.Difficulty, "difficulty preset: easy, normal, nightmare or custom:key=value,...")
+	sellRefund := fs.Float64("sell-refund", opts.config.SellRefund, "share of an item's price selling refunds, from 0 to 1, overriding the difficulty (0 keeps its rate)")
+	noColor := fs.Bool("no-color", opts.config.NoColor, "draw without colors (same as --theme mono; also set by NO_COLOR)")
+	themeName := fs.String("theme", opts.config.Theme, "color theme: default, colorblind, mono or a custom theme name")
+	ascii := fs.Bool("ascii", opts.config.ASCII, "draw with plain ASCII characters only")
+	noEffects := fs.Bool("no-effects", opts.config.NoEffects, "turn off combat animations such as shots and damage numbers")
+	layout := fs.String("layout", opts.config.Layout, "panel layout: auto, wide or tabs")
+	lang := fs.String("lang", opts.config.Lang, "language: "+strings.Join(LocaleNames(), ", ")+" or auto to follow LANG")
+	newGame := fs.Bool("new", false, "start a new game even if the slot has a save")
+	eventsPath := fs.String("events", "", "write every engine event to this JSON Lines file")
+	tutorialMode := fs.Bool("tutorial", false, "play the guided tutorial (not saved or recorded)")
+	keymapPath := fs.String("keymap", "", "key bindings file (default keymap.json next to the config file)")
+	httpAddr := fs.String("http", "", "serve a read-only JSON and Server-Sent Events API of the game on this address, e.g. localhost:8080")
+	hostAddr := fs.String("host", "", "host a multiplayer game on this address, e.g. localhost:7777 (not saved or recorded)")
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	if code, ok := opts.check("play"); !ok {
+		return code
+	}
+	if *hostAddr != "" && *tutorialMode {
+		fmt.Fprintln(os.Stderr, "play: --host and --tutorial cannot be combined")
+		return exitUsage
+	}
+	if err := SelectLocale(*lang); err != nil {
+		fmt.Fprintf(os.Stderr, "play: %v\n", err)
+		return exitUsage
+	}
+	if err := validateLayout(*layout); err != nil {
+		fmt.Fprintf(os.Stderr, "play: %v\n", err)
+		return exitUsage
+	}
+	// https://no-color.org: any non-empty NO_COLOR disables colors
+	if *noColor || os.Getenv("NO_COLOR") != "" {
+		*themeName = "mono"
+	}
+	theme, err := LoadTheme(opts.configPath, *themeName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "play: %v\n", err)
+		return exitUsage
+	}
+	showSymbols = theme.Symbols
+	if *keymapPath == "" {
+		*keymapPath = DefaultKeymapPath(opts.configPath)
+	}
+	keymap, err := LoadKeymap(*keymapPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "play: %v\n", err)
+		return exitError
+	}
+
+	d, err := ParseDifficulty(*difficultyName)
+	if err == nil {
+		err = validateSellRefund(*sellRefund)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "play: %v\n", err)
+		return exitUsage
+	}
+	d = d.WithSellRefund(*sellRefund)
+	SetDifficulty(d)
+	asciiMode = *ascii
+
+	var resume *Recording
+	if !*newGame && !*tutorialMode && *hostAddr == "" {
+		resume, err = LoadSave(opts.slotDir())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "play: %v\n", err)
+			return exitError
+		}
+	}
+	// A resumed game keeps the difficulty it was saved with, so one asked
+	// for on the command line must match it
+	if resume != nil && (flagPassed(fs, "difficulty") || flagPassed(fs, "sell-refund")) && recordedDifficulty(resume.Difficulty) != recordedDifficulty(d) {
+		fmt.Fprintln(os.Stderr, "play: the saved game in this slot was started on another difficulty; pass --new to start a new game")
+		return exitUsage
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	stopEvents, err := startEventLog(*eventsPath, nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "play: %v\n", err)
+		return exitError
+	}
+
+	if *hostAddr != "" {
+		hostServer, err = Listen(*hostAddr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "play: %v\n", err)
+			return exitError
+		}
+		defer hostServer.Close()
+	}
+	if *httpAddr != "" {
+		spectator, err = ListenSpectator(*httpAddr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "play: %v\n", err)
+			return exitError
+		}
+		defer spectator.Close()
+	}
+
+	code := runTUI(tuiOptions{
+		slotDir:    opts.slotDir(),
+		seed:       *seed,
+		resume:     resume,
+		theme:      theme,
+		keymap:     keymap,
+		tutorial:   *tutorialMode,
+		layout:     *layout,
+		effects:    !*noEffects,
+		sellRefund: *sellRefund,
+		configPath: opts.configPath,
+	})
+	if err := stopEvents(); err != nil {
+		fmt.Fprintf(os.Stderr, "play: %v\n", err)
+		return exitError
+	}
+	return code
+}
+
+// RunJoin implements the join subcommand: take over a dreamer in a game
+// someone hosts with play --host
+func RunJoin(args []string) int {
+	fs := flag.NewFlagSet("join", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s join [flags] address\n\nThe address is the one the host passed to play --host.\n\n", programName())
+		fs.PrintDefaults()
+	}
+	opts := addConfigFlags(fs, args)
+	name := fs.String("name", os.Getenv("USER"), "your player name")
+	dreamer := fs.String("dreamer", "", "dreamer to play: Luna, Morpheus, Nyx or Hypnos (default the first free one)")
+	ascii := fs.Bool("ascii", opts.config.ASCII, "draw with plain ASCII characters only")
+	lang := fs.String("lang", opts.config.Lang, "language: "+strings.Join(LocaleNames(), ", ")+" or auto to follow LANG")
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	if code, ok := opts.check("join"); !ok {
+		return code
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return exitUsage
+	}
+	if strings.TrimSpace(*name) == "" {
+		fmt.Fprintln(os.Stderr, "join: a player name is required (--name)")
+		return exitUsage
+	}
+	if err := SelectLocale(*lang); err != nil {
+		fmt.Fprintf(os.Stderr, "join: %v\n", err)
+		return exitUsage
+	}
+	asciiMode = *ascii
+
+	conn, err := net.DialTimeout("tcp", fs.Arg(0), 5*time.Second)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "join: %v\n", err)
+		return exitError
+	}
+	defer conn.Close()
+	return runClient(conn, *name, *dreamer)
+}
+
+// flagPassed reports whether the flag name was given on the command line
+func flagPassed(fs *flag.FlagSet, name string) bool {
+	passed := false
+	fs.Visit(func(f *flag.Flag) {
+		passed = passed || f.Name == name
+	})
+	return passed
+}
+
+// RunReplay implements the replay subcommand
+func RunReplay(args []string) int {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s replay [flags] [file]\n\nWithout a file, replays the newest game in the slot.\n\n", programName())
+		fs.PrintDefaults()
+	}
+	opts := addConfigFlags(fs, args)
+	quiet := fs.Bool("quiet", false, "only print the outcome")
+	eventsPath := fs.String("events", "", "write every engine event to this JSON Lines file")
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	if code, ok := opts.check("replay"); !ok {
+		return code
+	}
+
+	path := fs.Arg(0)
+	if path == "" {
+		var err error
+		if path, err = LatestReplay(opts.slotDir()); err != nil {
+			fmt.Fprintf(os.Stderr, "replay: %v\n", err)
+			return exitError
+		}
+	}
+	rec, err := readRecording(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "replay: %v\n", err)
+		return exitError
+	}
+
+	stopEvents, err := startEventLog(*eventsPath, nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "replay: %v\n", err)
+		return exitError
+	}
+	fmt.Printf("Replaying %s (seed %d, %s)\n\n", path, rec.Seed, rec.Difficulty.Name)
+	ReplayRecording(rec, nil, func(a RecordedAction) {
+		if !*quiet {
+			fmt.Printf("  t=%7.1fs  %-8s %s\n", a.At.Seconds(), a.Kind, describeAction(a))
+		}
+	})
+	if err := stopEvents(); err != nil {
+		fmt.Fprintf(os.Stderr, "replay: %v\n", err)
+		return exitError
+	}
+
+	outcome := "in progress"
+	if gameState.gameOver && gameState.gameWon {
+		outcome = "won"
+	} else if gameState.gameOver {
+		outcome = "lost"
+	}
+	fmt.Printf("\nResult: %s after %.1fs\n", outcome, gameState.elapsed.Seconds())
+	// A finished game must also end at the moment it was recorded ending
+	if gameState.gameOver != rec.Over || gameState.gameWon != rec.Won ||
+		rec.Over && gameState.elapsed != rec.Elapsed {
+		fmt.Fprintln(os.Stderr, "replay: result does not match the recording")
+		return exitError
+	}
+	return exitOK
+}
+
+// describeAction names the item an action is about to affect
+func describeAction(a RecordedAction) string {
+	switch a.Kind {
+	case ActionBuy:
+		items := GetAvailableItemsByCategory(a.Category)
+		if a.Index >= 0 && a.Index < len(items) {
+			if a.Count > 1 {
+				return fmt.Sprintf("%s x%d", items[a.Index].name, a.Count)
+			}
+			return items[a.Index].name
+		}
+	case ActionUpgrade, ActionSell:
+		if a.Index >= 0 && a.Index < len(gameState.itemsPanelItems) {
+			return gameState.itemsPanelItems[a.Index]
+		}
+	case ActionSpawn:
+		return fmt.Sprintf("hunter level %d", gameState.hunterLevel)
+	case ActionAutoBuyerToggle, ActionAutoBuyerReserve, ActionAutoBuyerIdle:
+		if a.Index >= 0 && a.Index < len(autoBuyerKinds) {
+			return autoBuyerKinds[a.Index].name
+		}
+	}
+	return ""
+}
+
+// RunStats implements the stats subcommand
+func RunStats(args []string) int {
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	opts := addConfigFlags(fs, args)
+	format := fs.String("format", "text", "output format: text or json")
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	if code, ok := opts.check("stats"); !ok {
+		return code
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "stats: unknown format %q\n", *format)
+		return exitUsage
+	}
+
+	stats, err := LoadStats(opts.slotDir())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "stats: %v\n", err)
+		return exitError
+	}
+	if *format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(stats); err != nil {
+			return exitError
+		}
+		return exitOK
+	}
+
+	if len(stats.ByDifficulty) == 0 {
+		fmt.Printf("No finished games in slot %s yet.\n", opts.slot)
+		return exitOK
+	}
+	names := []string{}
+	for name := range stats.ByDifficulty {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Printf("Slot %s\n\n%-10s %6s %6s %8s %10s %10s\n", opts.slot, "difficulty", "games", "wins", "win rate", "best win", "avg game")
+	for _, name := range names {
+		d := stats.ByDifficulty[name]
+		best := "-"
+		if d.Wins > 0 {
+			best = fmt.Sprintf("%.1fs", d.BestWinSeconds)
+		}
+		fmt.Printf("%-10s %6d %6d %7.0f%% %10s %9.1fs\n", name, d.Games, d.Wins,
+			100*float64(d.Wins)/float64(d.Games), best, d.TotalSeconds/float64(d.Games))
+	}
+	return exitOK
+}
+
+// RunConfig implements the config subcommand:
+//
+//	config              print every setting
+//	config path         print the config file path
+//	config get KEY      print one setting
+//	config set KEY VAL  change one setting
+func RunConfig(args []string) int {
+	fs := flag.NewFlagSet("config", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s config [flags] [path | get KEY | set KEY VALUE]\n\nKeys: %s\n\n",
+			programName(), strings.Join(ConfigKeys(), ", "))
+		fs.PrintDefaults()
+	}
+	configPath := fs.String("config", DefaultConfigPath(), "config file path")
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+
+	cfg, err := LoadConfig(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "config: %v\n", err)
+		return exitError
+	}
+
+	rest := fs.Args()
+	switch {
+	case len(rest) == 0:
+		for _, key := range ConfigKeys() {
+			value, _ := cfg.Get(key)
+			fmt.Printf("%s = %s\n", key, value)
+		}
+	case rest[0] == "path" && len(rest) == 1:
+		fmt.Println(*configPath)
+	case rest[0] == "get" && len(rest) == 2:
+		value, err := cfg.Get(rest[1])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "config: %v\n", err)
+			return exitUsage
+		}
+		fmt.Println(value)
+	case rest[0] == "set" && len(rest) == 3:
+		if err := cfg.Set(rest[1], rest[2]); err != nil {
+			fmt.Fprintf(os.Stderr, "config: %v\n", err)
+			return exitUsage
+		}
+		if err := cfg.Save(*configPath); err != nil {
+			fmt.Fprintf(os.Stderr, "config: %v\n", err)
+			return exitError
+		}
+	default:
+		fs.Usage()
+		return exitUsage
+	}
+	return exitOK
+}
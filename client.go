@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// clientKeys are the join client's buy keys, by remote shop item
+var clientKeys = map[rune]string{'b': remoteBed, 'd': remoteDoor, 'g': remotePistol}
+
+// clientView is what the join client knows about the game
+type clientView struct {
+	addr    string
+	name    string
+	dreamer string    // dreamer we play, empty until the host welcomes us
+	state   *netState // newest state from the host
+	notice  string    // last error from the host, or why we disconnected
+}
+
+// runClient plays a dreamer in the game at the other end of conn until
+// the player quits
+func runClient(conn net.Conn, name, dreamer string) int {
+	app := tview.NewApplication()
+	view := &clientView{addr: conn.RemoteAddr().String(), name: name}
+
+	panel := tview.NewTextView().SetDynamicColors(true).SetScrollable(false)
+	panel.SetBorder(true).SetTitle(" " + T("title.client") + " ")
+	help := tview.NewTextView().SetDynamicColors(true).
+		SetText(fmt.Sprintf("[yellow]%s[white] [yellow]b/d/g:[white]%s  [yellow]q:[white]%s", T("help.keys"), T("help.Buy"), T("help.Quit")))
+	root := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(panel, 0, 1, false).
+		AddItem(help, 1, 0, false)
+
+	send := func(msg netMessage) {
+		line, _ := json.Marshal(msg)
+		conn.Write(append(line, '\n'))
+	}
+
+	go func() {
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			var msg netMessage
+			if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+				continue
+			}
+			app.QueueUpdateDraw(func() {
+				switch msg.Type {
+				case "welcome":
+					view.dreamer, view.notice = msg.Dreamer, ""
+				case "error":
+					view.notice = msg.Error
+				case "state":
+					view.state = msg.State
+				}
+				view.draw(panel)
+			})
+		}
+		app.QueueUpdateDraw(func() {
+			view.notice = T("client.disconnected")
+			view.state = nil
+			view.draw(panel)
+		})
+	}()
+
+	app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyCtrlC || event.Rune() == 'q' {
+			app.Stop()
+			return nil
+		}
+		if item, ok := clientKeys[event.Rune()]; ok {
+			view.notice = ""
+			send(netMessage{Type: "buy", Item: item})
+			return nil
+		}
+		return event
+	})
+
+	send(netMessage{Type: "join", Name: name, Dreamer: dreamer})
+	view.draw(panel)
+	if err := app.SetRoot(root, true).Run(); err != nil {
+		return exitError
+	}
+	return exitOK
+}
+
+// draw shows the hunter, every door and our economy and shop
+func (v *clientView) draw(panel *tview.TextView) {
+	panel.Clear()
+	if v.dreamer == "" {
+		fmt.Fprintf(panel, "%s\n", T("client.joining", v.addr))
+	} else {
+		fmt.Fprintf(panel, "[green]%s[white]\n", T("client.playing", tview.Escape(v.dreamer), tview.Escape(v.name), v.addr))
+	}
+
+	if st := v.state; st != nil {
+		fmt.Fprintln(panel)
+		if st.Hunter.Active {
+			target := st.Hunter.Target
+			if target == "host" {
+				target = T("client.host")
+			}
+			fmt.Fprintf(panel, "[red]%s[white] %s %d/%d\n", T("client.hunter", st.Hunter.Level, tview.Escape(target)),
+				DrawHPBar(st.Hunter.HP, st.Hunter.MaxHP, 15), st.Hunter.HP, st.Hunter.MaxHP)
+		} else {
+			fmt.Fprintf(panel, "[gray]%s[white]\n", T("client.no_hunter"))
+		}
+
+		fmt.Fprintf(panel, "\n[yellow]%s[white]\n", T("ui.dreamers"))
+		for _, door := range st.Doors {
+			name := door.Name
+			if name == "host" {
+				name = T("client.host")
+			}
+			who := ""
+			if door.Player != "" {
+				who = " (" + tview.Escape(door.Player) + ")"
+			}
+			fmt.Fprintf(panel, "%s %-8s %s %d/%d%s%s\n", doorMark(door.HP, door.MaxHP), tview.Escape(name),
+				T("ui.door_level", door.Level), door.HP, door.MaxHP, hpMark(door.HP, door.MaxHP), who)
+		}
+
+		out := false
+		for _, door := range st.Doors {
+			out = out || door.Name == v.dreamer && door.HP == 0
+		}
+		if you := st.You; out {
+			fmt.Fprintf(panel, "\n[red]%s[white]\n", T("client.eliminated"))
+		} else if you != nil {
+			fmt.Fprintf(panel, "\n%s  %s\n", T("ui.coins", FormatNumber(you.Coins), FormatNumber(you.CoinsPerS)),
+				T("client.guns", you.Guns))
+			fmt.Fprintf(panel, "\n[yellow]%s[white]\n", T("client.shop"))
+			for _, item := range you.Shop {
+				key := ' '
+				for r, id := range clientKeys {
+					if id == item.ID {
+						key = r
+					}
+				}
+				color := "white"
+				if item.Cost > you.Coins {
+					color = "gray"
+				}
+				fmt.Fprintf(panel, "[%s]%c) %s Lv%d - %s (%s)[white]\n", color, key, itemName(item.Name), item.Level,
+					costString(BigNum(item.Cost), 0), item.Description)
+			}
+		}
+
+		if st.Over {
+			msg := T("client.lost")
+			if st.Won {
+				msg = T("client.won")
+			}
+			fmt.Fprintf(panel, "\n[yellow]%s[white]\n", msg)
+		}
+	}
+
+	if v.notice != "" {
+		fmt.Fprintf(panel, "\n[red]%s[white]\n", tview.Escape(strings.TrimSpace(v.notice)))
+	}
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+// Config holds the persistent defaults for command-line flags
+type Config struct {
+	Difficulty string  `json:"difficulty"`
+	Slot       string  `json:"slot"`
+	NoColor    bool    `json:"no_color"`
+	ASCII      bool    `json:"ascii"`
+	NoEffects  bool    `json:"no_effects"`
+	Layout     string  `json:"layout"`
+	Theme      string  `json:"theme"`
+	Lang       string  `json:"lang"`                  // language code, or "auto" to follow LANG
+	SellRefund float64 `json:"sell_refund,omitempty"` // share of the price selling refunds; 0 keeps the difficulty's
+}
+
+// DefaultConfig is used when no config file exists
+func DefaultConfig() Config {
+	return Config{
+		Difficulty: "normal",
+		Slot:       "1",
+		Layout:     LayoutAuto,
+		Theme:      "default",
+		Lang:       "auto",
+	}
+}
+
+// DefaultConfigPath returns the config file location under the user's
+// config directory, falling back to the working directory
+func DefaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = "."
+	}
+	return filepath.Join(dir, "haunted-dorm", "config.json")
+}
+
+// LoadConfig reads the config at path. A missing file yields the defaults.
+func LoadConfig(path string) (Config, error) {
+	cfg := DefaultConfig()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("%s: %v", path, err)
+	}
+	return cfg, nil
+}
+
+// Save writes the config to path, creating its directory if needed
+func (c Config) Save(path string) error {
+	return writeJSONFile(path, c)
+}
+
+// configKeys maps config keys to getters and setters
+var configKeys = map[string]struct {
+	get func(c *Config) string
+	set func(c *Config, value string) error
+}{
+	"difficulty": {
+		get: func(c *Config) string { return c.Difficulty },
+		set: func(c *Config, v string) error {
+			if _, err := ParseDifficulty(v); err != nil {
+				return err
+			}
+			c.Difficulty = v
+			return nil
+		},
+	},
+	"slot": {
+		get: func(c *Config) string { return c.Slot },
+		set: func(c *Config, v string) error {
+			if err := validateSlot(v); err != nil {
+				return err
+			}
+			c.Slot = v
+			return nil
+		},
+	},
+	"lang": {
+		get: func(c *Config) string { return c.Lang },
+		set: func(c *Config, v string) error {
+			if err := validateLang(v); err != nil {
+				return err
+			}
+			c.Lang = v
+			return nil
+		},
+	},
+	"layout": {
+		get: func(c *Config) string { return c.Layout },
+		set: func(c *Config, v string) error {
+			if err := validateLayout(v); err != nil {
+				return err
+			}
+			c.Layout = v
+			return nil
+		},
+	},
+	"theme": {
+		get: func(c *Config) string { return c.Theme },
+		set: func(c *Config, v string) error {
+			if _, builtin := builtinThemes[v]; !builtin {
+				if err := validateThemeName(v); err != nil {
+					return err
+				}
+			}
+			c.Theme = v
+			return nil
+		},
+	},
+	"no_color": {
+		get: func(c *Config) string { return strconv.FormatBool(c.NoColor) },
+		set: func(c *Config, v string) (err error) {
+			c.NoColor, err = strconv.ParseBool(v)
+			return err
+		},
+	},
+	"ascii": {
+		get: func(c *Config) string { return strconv.FormatBool(c.ASCII) },
+		set: func(c *Config, v string) (err error) {
+			c.ASCII, err = strconv.ParseBool(v)
+			return err
+		},
+	},
+	"sell_refund": {
+		get: func(c *Config) string { return strconv.FormatFloat(c.SellRefund, 'g', -1, 64) },
+		set: func(c *Config, v string) error {
+			refund, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return err
+			}
+			if err := validateSellRefund(refund); err != nil {
+				return err
+			}
+			c.SellRefund = refund
+			return nil
+		},
+	},
+	"no_effects": {
+		get: func(c *Config) string { return strconv.FormatBool(c.NoEffects) },
+		set: func(c *Config, v string) (err error) {
+			c.NoEffects, err = strconv.ParseBool(v)
+			return err
+		},
+	},
+}
+
+// ConfigKeys returns the settable config keys in sorted order
+func ConfigKeys() []string {
+	keys := []string{}
+	for key := range configKeys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Get returns the value of key as a string
+func (c *Config) Get(key string) (string, error) {
+	k, ok := configKeys[key]
+	if !ok {
+		return "", fmt.Errorf("unknown config key %q", key)
+	}
+	return k.get(c), nil
+}
+
+// Set parses and stores value under key
+func (c *Config) Set(key, value string) error {
+	k, ok := configKeys[key]
+	if !ok {
+		return fmt.Errorf("unknown config key %q", key)
+	}
+	return k.set(c, value)
+}
+
+// writeJSONFile writes v as indented JSON, creating parent directories
+func writeJSONFile(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// readJSONFile reads JSON from path into v
+func readJSONFile(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Difficulty holds the tuning constants behind the core formulas
+type Difficulty struct {
+	Name string
+
+	HunterHPBase       float64 // HP₀ in GetHunterHP
+	HunterHPGrowth     float64 // r in GetHunterHP
+	HunterAttackBase   float64 // ATK₀ in GetHunterAttack
+	HunterAttackGrowth float64 // s in GetHunterAttack
+	DoorHPBase         int     // HP₀ in GetDoorHP
+	DoorHPPerLevel     int     // a in GetDoorHP
+	PriceMultiplier    float64 // applied to every shop price
+	SellRefund         float64 // share of the price refunded when selling
+
+	HunterAttackInterval time.Duration // time between hunter attacks on the door
+	SpawnInterval        int           // seconds between hunter spawns, give or take a quarter
+}
+
+var difficultyPresets = map[string]Difficulty{
+	"easy": {
+		Name:                 "easy",
+		HunterHPBase:         350,
+		HunterHPGrowth:       1.3,
+		HunterAttackBase:     35,
+		HunterAttackGrowth:   1.2,
+		DoorHPBase:           2500,
+		DoorHPPerLevel:       400,
+		PriceMultiplier:      0.8,
+		SellRefund:           0.75,
+		HunterAttackInterval: 4 * time.Second,
+		SpawnInterval:        20,
+	},
+	"normal": {
+		Name:                 "normal",
+		HunterHPBase:         500,
+		HunterHPGrowth:       1.4,
+		HunterAttackBase:     50,
+		HunterAttackGrowth:   1.25,
+		DoorHPBase:           2000,
+		DoorHPPerLevel:       300,
+		PriceMultiplier:      1,
+		SellRefund:           0.5,
+		HunterAttackInterval: 3 * time.Second,
+		SpawnInterval:        10,
+	},
+	"nightmare": {
+		Name:                 "nightmare",
+		HunterHPBase:         800,
+		HunterHPGrowth:       1.6,
+		HunterAttackBase:     80,
+		HunterAttackGrowth:   1.35,
+		DoorHPBase:           1500,
+		DoorHPPerLevel:       250,
+		PriceMultiplier:      1.5,
+		SellRefund:           0.25,
+		HunterAttackInterval: 2 * time.Second,
+		SpawnInterval:        6,
+	},
+}
+
+// DifficultyNames lists the presets in increasing order of difficulty
+var DifficultyNames = []string{"easy", "normal", "nightmare"}
+
+// difficulty is the preset used by the formulas; set it before InitGame
+var difficulty = difficultyPresets["normal"]
+
+// SetDifficulty selects the preset for the next game
+func SetDifficulty(d Difficulty) {
+	difficulty = d
+}
+
+// ParseDifficulty resolves a preset name. "custom:key=value,..." starts
+// from Normal and overrides individual constants, e.g.
+// "custom:hunter_hp=600,prices=1.2,sell_refund=0.6,attack_interval=2.5,spawn_interval=8".
+func ParseDifficulty(spec string) (Difficulty, error) {
+	name, overrides, _ := strings.Cut(strings.ToLower(spec), ":")
+	if name != "custom" {
+		d, ok := difficultyPresets[name]
+		if !ok {
+			return Difficulty{}, fmt.Errorf("unknown difficulty %q (want %s or custom)", spec, strings.Join(DifficultyNames, ", "))
+		}
+		return d, nil
+	}
+
+	d := difficultyPresets["normal"]
+	d.Name = "custom"
+	if overrides == "" {
+		return d, nil
+	}
+	for _, pair := range strings.Split(overrides, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return Difficulty{}, fmt.Errorf("custom difficulty: %q is not key=value", pair)
+		}
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil || v <= 0 {
+			return Difficulty{}, fmt.Errorf("custom difficulty: %s must be a positive number", key)
+		}
+		switch key {
+		case "door_hp", "door_hp_per_level", "spawn_interval":
+			if v != math.Trunc(v) || v > math.MaxInt32 {
+				return Difficulty{}, fmt.Errorf("custom difficulty: %s must be a positive whole number", key)
+			}
+		}
+		switch key {
+		case "hunter_hp":
+			d.HunterHPBase = v
+		case "hunter_hp_growth":
+			d.HunterHPGrowth = v
+		case "hunter_atk":
+			d.HunterAttackBase = v
+		case "hunter_atk_growth":
+			d.HunterAttackGrowth = v
+		case "door_hp":
+			d.DoorHPBase = int(v)
+		case "door_hp_per_level":
+			d.DoorHPPerLevel = int(v)
+		case "prices":
+			d.PriceMultiplier = v
+		case "sell_refund":
+			if v > 1 {
+				return Difficulty{}, fmt.Errorf("custom difficulty: sell_refund must be at most 1")
+			}
+			d.SellRefund = v
+		case "attack_interval":
+			d.HunterAttackInterval = time.Duration(v * float64(time.Second))
+		case "spawn_interval":
+			d.SpawnInterval = int(v)
+		default:
+			return Difficulty{}, fmt.Errorf("custom difficulty: unknown key %q", key)
+		}
+	}
+	return d, nil
+}
+
+// validateSellRefund checks a sell refund override: a share of the price
+// from 0 to 1, where 0 keeps the difficulty's own rate
+func validateSellRefund(refund float64) error {
+	if refund < 0 || refund > 1 {
+		return fmt.Errorf("sell refund must be between 0 and 1 (0 keeps the difficulty's rate)")
+	}
+	return nil
+}
+
+// WithSellRefund overrides the difficulty's sell refund rate unless refund
+// is 0. A preset with another rate is no longer that preset.
+func (d Difficulty) WithSellRefund(refund float64) Difficulty {
+	if refund == 0 || refund == d.SellRefund {
+		return d
+	}
+	d.SellRefund = refund
+	d.Name = "custom"
+	return d
+}
+
+// scalePrice applies the difficulty's price multiplier to a base price
+func scalePrice(base int) BigNum {
+	return BigNum(math.Round(float64(base) * difficulty.PriceMultiplier))
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseDifficulty(t *testing.T) {
+	normal := difficultyPresets["normal"]
+	custom := func(change func(*Difficulty)) Difficulty {
+		d := normal
+		d.Name = "custom"
+		change(&d)
+		return d
+	}
+	tests := []struct {
+		spec string
+		want Difficulty
+		err  string // part of the error, or "" for none
+	}{
+		{spec: "easy", want: difficultyPresets["easy"]},
+		{spec: "Nightmare", want: difficultyPresets["nightmare"]},
+		{spec: "custom", want: custom(func(*Difficulty) {})},
+		{spec: "custom:hunter_hp=600,prices=1.2", want: custom(func(d *Difficulty) {
+			d.HunterHPBase = 600
+			d.PriceMultiplier = 1.2
+		})},
+		{spec: "custom:attack_interval=2.5,spawn_interval=8,sell_refund=1", want: custom(func(d *Difficulty) {
+			d.HunterAttackInterval = 2500 * time.Millisecond
+			d.SpawnInterval = 8
+			d.SellRefund = 1
+		})},
+		{spec: "impossible", err: "unknown difficulty"},
+		{spec: "custom:prices", err: "is not key=value"},
+		{spec: "custom:prices=0", err: "must be a positive number"},
+		{spec: "custom:prices=cheap", err: "must be a positive number"},
+		{spec: "custom:door_hp=2.5", err: "must be a positive whole number"},
+		{spec: "custom:spawn_interval=1e12", err: "must be a positive whole number"},
+		{spec: "custom:sell_refund=1.5", err: "at most 1"},
+		{spec: "custom:luck=7", err: "unknown key"},
+	}
+	for _, tt := range tests {
+		got, err := ParseDifficulty(tt.spec)
+		switch {
+		case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+			t.Errorf("ParseDifficulty(%q) error = %v, want %q", tt.spec, err, tt.err)
+		case tt.err == "" && err != nil:
+			t.Errorf("ParseDifficulty(%q) error = %v", tt.spec, err)
+		case tt.err == "" && got != tt.want:
+			t.Errorf("ParseDifficulty(%q) = %+v, want %+v", tt.spec, got, tt.want)
+		}
+	}
+}
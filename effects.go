@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// Effect kinds
+const (
+	effectShot = iota
+	effectDamage
+	effectDoorFlash
+	effectHunterDeath
+)
+
+// effectDurations is how long each kind of effect stays on screen
+var effectDurations = map[int]time.Duration{
+	effectShot:        150 * time.Millisecond,
+	effectDamage:      800 * time.Millisecond,
+	effectDoorFlash:   300 * time.Millisecond,
+	effectHunterDeath: 1200 * time.Millisecond,
+}
+
+// deathFrames are the hunter's death animation, played in order
+var deathFrames = [][2]string{{"✖", "X"}, {"✦", "*"}, {"·", "."}, {" ", " "}}
+
+// effect is one running animation. Target is a map row: 0 is your room,
+// i+1 is dreamer i.
+type effect struct {
+	kind   int
+	target int
+	gun    string
+	damage int
+	start  time.Time
+}
+
+// Effects turns combat events into short animations. They run on the
+// wall clock, so they keep playing while the game is paused or over and
+// never hold up the engine.
+type Effects struct {
+	active []effect
+	now    func() time.Time
+}
+
+// effects is nil while effects are turned off
+var effects *Effects
+
+// NewEffects returns an empty set of effects
+func NewEffects() *Effects {
+	return &Effects{now: time.Now}
+}
+
+// Handle starts the effects for an engine event
+func (fx *Effects) Handle(e Event) {
+	if fx == nil {
+		return
+	}
+	start := fx.now()
+	switch e.Type {
+	case EventShot:
+		// Only your own guns are drawn; a networked player's shots just
+		// show their damage
+		if e.Dreamer == "" {
+			fx.active = append(fx.active, effect{kind: effectShot, gun: e.Gun, start: start})
+		}
+		fx.active = append(fx.active, effect{kind: effectDamage, target: gameState.hunterPos, damage: e.Damage, start: start})
+	case EventHunterAttack:
+		fx.active = append(fx.active, effect{kind: effectDoorFlash, target: 0, start: start})
+	case EventDreamerHit:
+		for i, char := range gameState.rooms[0].characters {
+			if char.name == e.Dreamer {
+				fx.active = append(fx.active, effect{kind: effectDoorFlash, target: i + 1, start: start})
+			}
+		}
+	case EventGameOver:
+		if e.Won != nil && *e.Won {
+			fx.active = append(fx.active, effect{kind: effectHunterDeath, target: gameState.hunterPos, start: start})
+		}
+	}
+}
+
+// prune drops finished effects
+func (fx *Effects) prune() {
+	if fx == nil {
+		return
+	}
+	now := fx.now()
+	kept := fx.active[:0]
+	for _, e := range fx.active {
+		if now.Sub(e.start) < effectDurations[e.kind] {
+			kept = append(kept, e)
+		}
+	}
+	fx.active = kept
+}
+
+// Busy reports whether the hunter's death animation is still playing
+func (fx *Effects) Busy() bool {
+	if fx == nil {
+		return false
+	}
+	fx.prune()
+	for _, e := range fx.active {
+		if e.kind == effectHunterDeath {
+			return true
+		}
+	}
+	return false
+}
+
+// Firing reports whether a gun of that name just shot
+func (fx *Effects) Firing(gun string) bool {
+	if fx == nil {
+		return false
+	}
+	for _, e := range fx.active {
+		if e.kind == effectShot && e.gun == gun {
+			return true
+		}
+	}
+	return false
+}
+
+// DoorFlash reports whether the door of map row target was just hit
+func (fx *Effects) DoorFlash(target int) bool {
+	if fx == nil {
+		return false
+	}
+	for _, e := range fx.active {
+		if e.kind == effectDoorFlash && e.target == target {
+			return true
+		}
+	}
+	return false
+}
+
+// DeathFrame returns the frame of the death animation shown on map row
+// target, if it is playing there
+func (fx *Effects) DeathFrame(target int) (string, bool) {
+	if fx == nil {
+		return "", false
+	}
+	for _, e := range fx.active {
+		if e.kind == effectHunterDeath && e.target == target {
+			frame := int(fx.now().Sub(e.start) * time.Duration(len(deathFrames)) / effectDurations[e.kind])
+			if frame >= len(deathFrames) {
+				frame = len(deathFrames) - 1
+			}
+			return glyph(deathFrames[frame][0], deathFrames[frame][1]), true
+		}
+	}
+	return "", false
+}
+
+// DamageNumbers draws the damage dealt on map row target. Numbers drift
+// away from the corridor and fade as they age; at most the newest three
+// are shown.
+func (fx *Effects) DamageNumbers(target int) string {
+	if fx == nil {
+		return ""
+	}
+	now := fx.now()
+	out := ""
+	shown := 0
+	for i := len(fx.active) - 1; i >= 0 && shown < 3; i-- {
+		e := fx.active[i]
+		if e.kind != effectDamage || e.target != target {
+			continue
+		}
+		age := now.Sub(e.start)
+		color := "white"
+		if age > effectDurations[e.kind]/2 {
+			color = "gray"
+		}
+		drift := int(age / (200 * time.Millisecond))
+		out += fmt.Sprintf(" %*s[%s]-%d[white]", drift, "", color, e.damage)
+		shown++
+	}
+	return out
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+)
+
+// Event is a machine-readable record of something the engine did.
+// Fields that do not apply to an event type are left out.
+type Event struct {
+	Game     *int    `json:"game,omitempty"` // game index within a sim batch
+	Time     float64 `json:"t"`              // game seconds
+	Type     string  `json:"type"`
+	Gun      string  `json:"gun,omitempty"`
+	Item     string  `json:"item,omitempty"`
+	Dreamer  string  `json:"dreamer,omitempty"`
+	Level    int     `json:"level,omitempty"`
+	Count    int     `json:"count,omitempty"` // items bought in one stack; left out for one
+	Damage   int     `json:"damage,omitempty"`
+	HunterHP *int    `json:"hunter_hp,omitempty"`
+	DoorHP   *int    `json:"door_hp,omitempty"`
+	Coins    float64 `json:"coins,omitempty"`    // price paid in coins, or refunded for a sale
+	Diamonds float64 `json:"diamonds,omitempty"` // price paid in diamonds, or refunded for a sale
+	Won      *bool   `json:"won,omitempty"`
+}
+
+// Event types
+const (
+	EventSpawn          = "spawn"
+	EventShot           = "shot"
+	EventHunterAttack   = "hunter_attack"
+	EventDreamerHit     = "dreamer_hit"
+	EventDreamerUpgrade = "dreamer_upgrade"
+	EventPurchase       = "purchase"
+	EventUpgrade        = "upgrade"
+	EventSell           = "sell"
+	EventGameOver       = "game_over"
+)
+
+// eventListeners receive every emitted event, in registration order
+var eventListeners []func(Event)
+
+// AddEventListener subscribes fn to engine events
+func AddEventListener(fn func(Event)) {
+	eventListeners = append(eventListeners, fn)
+}
+
+// emitEvent stamps e with the game time and hands it to the listeners
+func emitEvent(e Event) {
+	if len(eventListeners) == 0 {
+		return
+	}
+	e.Time = gameState.elapsed.Seconds()
+	for _, fn := range eventListeners {
+		fn(e)
+	}
+}
+
+// intPtr and boolPtr let zero values survive omitempty
+func intPtr(v int) *int    { return &v }
+func boolPtr(v bool) *bool { return &v }
+
+// JSONLSink writes events as JSON Lines
+type JSONLSink struct {
+	mu  sync.Mutex
+	w   *bufio.Writer
+	enc *json.Encoder
+	c   io.Closer
+	err error
+}
+
+// NewJSONLSink writes to w and closes it on Close if it is an io.Closer
+func NewJSONLSink(w io.Writer) *JSONLSink {
+	bw := bufio.NewWriter(w)
+	s := &JSONLSink{w: bw, enc: json.NewEncoder(bw)}
+	if c, ok := w.(io.Closer); ok {
+		s.c = c
+	}
+	return s
+}
+
+// OpenJSONLSink creates (or truncates) path and writes events to it
+func OpenJSONLSink(path string) (*JSONLSink, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewJSONLSink(f), nil
+}
+
+// Emit writes one event; the first write error is kept for Close
+func (s *JSONLSink) Emit(e Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err == nil {
+		s.err = s.enc.Encode(e)
+	}
+}
+
+// Close flushes buffered events and closes the underlying writer
+func (s *JSONLSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.w.Flush(); err != nil && s.err == nil {
+		s.err = err
+	}
+	if s.c != nil {
+		if err := s.c.Close(); err != nil && s.err == nil {
+			s.err = err
+		}
+	}
+	return s.err
+}
+
+// startEventLog sends events to a JSONL file at path until the returned
+// function is called. An empty path does nothing.
+func startEventLog(path string, tag func(*Event)) (stop func() error, err error) {
+	if path == "" {
+		return func() error { return nil }, nil
+	}
+	sink, err := OpenJSONLSink(path)
+	if err != nil {
+		return nil, err
+	}
+	n := len(eventListeners)
+	AddEventListener(func(e Event) {
+		if tag != nil {
+			tag(&e)
+		}
+		sink.Emit(e)
+	})
+	return func() error {
+		eventListeners = eventListeners[:n]
+		return sink.Close()
+	}, nil
+}
@@ -1,7 +1,9 @@
 package main
 
 import (
-	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
 	"time"
 
 	"github.com/rivo/tview"
@@ -9,8 +11,8 @@ import (
 
 // Game state
 type GameState struct {
-	coins     int
-	diamonds  int
+	coins     BigNum
+	diamonds  BigNum
 	coinsPerS float64
 	diamPerS  float64
 
@@ -25,13 +27,15 @@ type GameState struct {
 	guns []Gun
 
 	// Hunter
-	hunterHP       int
-	hunterMaxHP    int
-	hunterPos      int
-	hunterActive   bool
-	hunterLevel    int
-	hunterAttack   int
-	lastAttackTime time.Time
+	hunterSpawnCounter int
+	hunterSpawnDelay   int // seconds the spawn timer runs until the next spawn
+	hunterHP           int
+	hunterMaxHP        int
+	hunterPos          int // door under attack: 0 is yours, i+1 is dreamer i
+	hunterActive       bool
+	hunterLevel        int
+	hunterAttack       int
+	lastAttackTime     time.Time
 
 	// Rooms (for spectate)
 	currentRoom int
@@ -45,9 +49,21 @@ type GameState struct {
 	gameOver bool
 	gameWon  bool
 
+	// Auto-buyers, in autoBuyerKinds order
+	autoBuyers []AutoBuyer
+
 	// Your Items panel selection
 	itemsPanelSelected int
 	itemsPanelItems    []string
+
+	// Random source for spawn timing and dreamer targeting
+	rng *rand.Rand
+
+	// Game time played so far; only advances through StepGame
+	elapsed time.Duration
+
+	// Name of the difficulty preset the game was started with
+	difficulty string
 }
 
 type Character struct {
@@ -58,6 +74,12 @@ type Character struct {
 	doorMaxHP       int
 	doorLevel       int
 	lastUpgradeTime time.Time
+
+	// Set while a networked player runs this dreamer's economy and shop
+	player   string
+	coins    BigNum
+	bedLevel int
+	guns     []Gun
 }
 
 type Gun struct {
@@ -66,6 +88,10 @@ type Gun struct {
 	damage      int
 	attackSpeed float64 // attacks per second
 	lastShot    time.Time
+
+	// What the gun cost, for the sell-back value
+	paidCoins    BigNum
+	paidDiamonds BigNum
 }
 
 type Room struct {
@@ -80,18 +106,35 @@ type Item struct {
 	name         string
 	currentLevel int
 	maxLevel     int
-	costCoins    int
-	costDiamonds int
+	costCoins    BigNum
+	costDiamonds BigNum
 	production   float64
 	description  string
-	itemType     string  // "bed", "door", "playbox", "trap", "guard", "gun"
+	itemType     string  // "bed", "door", "playbox", "trap", "guard", "gun", "autobuyer"
 	damage       int     // for guns
 	attackSpeed  float64 // for guns
 }
 
 var gameState *GameState
 
+// StepDuration is the game time covered by one StepGame call
+const StepDuration = 100 * time.Millisecond
+
+// gameEpoch is the game-clock time every game starts at
+var gameEpoch = time.Unix(0, 0)
+
+// timeNow is the engine clock. It follows game time rather than wall
+// time, so it stands still while paused and runs faster when sped up.
+func timeNow() time.Time {
+	return gameEpoch.Add(gameState.elapsed)
+}
+
 func InitGame() {
+	InitGameWithSeed(time.Now().UnixNano())
+}
+
+// InitGameWithSeed starts a new game whose random events are driven by seed
+func InitGameWithSeed(seed int64) {
 	gameState = &GameState{
 		coins:              0,
 		diamonds:           0,
@@ -109,7 +152,7 @@ func InitGame() {
 		hunterActive:       false,
 		hunterLevel:        1,
 		hunterAttack:       GetHunterAttack(1),
-		lastAttackTime:     time.Now(),
+		lastAttackTime:     gameEpoch,
 		currentRoom:        0,
 		playerDefense:      100,
 		playerMaxDefense:   100,
@@ -117,21 +160,25 @@ func InitGame() {
 		gameWon:            false,
 		itemsPanelSelected: 0,
 		itemsPanelItems:    []string{},
+		autoBuyers:         newAutoBuyers(),
+		rng:                rand.New(rand.NewSource(seed)),
+		difficulty:         difficulty.Name,
 		rooms: []Room{
 			{
 				name:  "Dream Realm",
 				items: []string{},
 				characters: []Character{
-					{name: "Luna", defense: 80, maxDefense: 80, doorHP: GetDoorHP(1), doorMaxHP: GetDoorHP(1), doorLevel: 1, lastUpgradeTime: time.Now()},
-					{name: "Morpheus", defense: 90, maxDefense: 90, doorHP: GetDoorHP(1), doorMaxHP: GetDoorHP(1), doorLevel: 1, lastUpgradeTime: time.Now()},
-					{name: "Nyx", defense: 70, maxDefense: 70, doorHP: GetDoorHP(1), doorMaxHP: GetDoorHP(1), doorLevel: 1, lastUpgradeTime: time.Now()},
-					{name: "Hypnos", defense: 85, maxDefense: 85, doorHP: GetDoorHP(1), doorMaxHP: GetDoorHP(1), doorLevel: 1, lastUpgradeTime: time.Now()},
+					{name: "Luna", defense: 80, maxDefense: 80, doorHP: GetDoorHP(1), doorMaxHP: GetDoorHP(1), doorLevel: 1, lastUpgradeTime: gameEpoch},
+					{name: "Morpheus", defense: 90, maxDefense: 90, doorHP: GetDoorHP(1), doorMaxHP: GetDoorHP(1), doorLevel: 1, lastUpgradeTime: gameEpoch},
+					{name: "Nyx", defense: 70, maxDefense: 70, doorHP: GetDoorHP(1), doorMaxHP: GetDoorHP(1), doorLevel: 1, lastUpgradeTime: gameEpoch},
+					{name: "Hypnos", defense: 85, maxDefense: 85, doorHP: GetDoorHP(1), doorMaxHP: GetDoorHP(1), doorLevel: 1, lastUpgradeTime: gameEpoch},
 				},
 				coinsPerS: 0,
 				diamPerS:  0,
 			},
 		},
 	}
+	gameState.hunterSpawnDelay = rollSpawnDelay()
 	updateItemsPanelList()
 }
 
@@ -154,9 +201,27 @@ func UpdateGame() {
 		gameState.diamPerS = float64(int(1) << shift) // 2^(level-1): 1,2,4,8,16...
 	}
 
-	// Add coins
-	gameState.coins += int(gameState.coinsPerS)
-	gameState.diamonds += int(gameState.diamPerS)
+	// Add coins, keeping fractions for the next tick
+	gameState.coins += BigNum(gameState.coinsPerS)
+	gameState.diamonds += BigNum(gameState.diamPerS)
+
+	updateRemoteEconomy()
+}
+
+// StepGame advances the game by one StepDuration: combat every step,
+// economy and hunter spawning once per game second
+func StepGame(logPanel *tview.TextView) {
+	if gameState.gameOver {
+		return
+	}
+	gameState.elapsed += StepDuration
+
+	UpdateCombat(logPanel)
+	if gameState.elapsed%time.Second == 0 && !gameState.gameOver {
+		UpdateGame()
+		RunAutoBuyers(logPanel)
+		UpdateHunterSpawn(logPanel)
+	}
 }
 
 func UpdateCombat(logPanel *tview.TextView) {
@@ -164,62 +229,71 @@ func UpdateCombat(logPanel *tview.TextView) {
 		return
 	}
 
-	now := time.Now()
+	now := timeNow()
 
 	// Guns shoot at hunter
 	for i := range gameState.guns {
-		gun := &gameState.guns[i]
-		interval := time.Duration(1000.0/gun.attackSpeed) * time.Millisecond
+		if fireGun(&gameState.guns[i], "", now, logPanel) {
+			return
+		}
+	}
+	if remoteGunsFire(now, logPanel) {
+		return
+	}
+
+	// Hunter attacks door every few seconds, depending on difficulty
+	if now.Sub(gameState.lastAttackTime) >= difficulty.HunterAttackInterval {
+		// With networked players in the game the hunter picks a door
+		// among everyone's, yours included
+		target := 0
+		if targets := controlledTargets(); len(targets) > 0 {
+			target = append([]int{0}, targets...)[gameState.rng.Intn(len(targets)+1)]
+		}
 
-		if now.Sub(gun.lastShot) >= interval {
-			gameState.hunterHP -= gun.damage
-			gun.lastShot = now
+		if target > 0 {
+			gameState.lastAttackTime = now
+			attackDreamer(target-1, logPanel)
+		} else {
+			gameState.doorHP -= gameState.hunterAttack
+			gameState.lastAttackTime = now
+			gameState.hunterPos = 0
+			if gameState.doorHP < 0 {
+				gameState.doorHP = 0
+			}
+			AddLog(logPanel, LogCombat, "[red]"+T("log.hunter_attacks", gameState.hunterAttack)+"[white]")
+			emitEvent(Event{Type: EventHunterAttack, Damage: gameState.hunterAttack, DoorHP: intPtr(gameState.doorHP)})
 
-			if gameState.hunterHP <= 0 {
-				gameState.hunterHP = 0
-				gameState.hunterActive = false
+			if gameState.doorHP <= 0 {
 				gameState.gameOver = true
-				gameState.gameWon = true
-				AddLog(logPanel, "[green]Dream Hunter defeated! YOU WIN![white]")
+				AddLog(logPanel, LogCombat, "[red]"+T("log.door_broken")+"[white]")
+				emitEvent(Event{Type: EventGameOver, Won: boolPtr(false)})
 				return
 			}
 		}
 	}
 
-	// Hunter attacks door every 3 seconds
-	if now.Sub(gameState.lastAttackTime) >= 3*time.Second {
-		gameState.doorHP -= gameState.hunterAttack
-		gameState.lastAttackTime = now
-		AddLog(logPanel, fmt.Sprintf("[red]Hunter attacks door! -%d HP[white]", gameState.hunterAttack))
-
-		if gameState.doorHP <= 0 {
-			gameState.doorHP = 0
-			gameState.gameOver = true
-			AddLog(logPanel, "[red]GAME OVER! Your door is broken![white]")
-			return
-		}
-	}
-
 	// Hunter attacks other dreamers' doors randomly
 	if now.Unix()%5 == 0 { // Every 5 seconds
 		// Find dreamers with doors still standing
 		aliveDreamers := []int{}
 		for i := range gameState.rooms[0].characters {
-			if gameState.rooms[0].characters[i].doorHP > 0 {
+			if gameState.rooms[0].characters[i].doorHP > 0 && !gameState.rooms[0].characters[i].controlled() {
 				aliveDreamers = append(aliveDreamers, i)
 			}
 		}
 
 		// Attack one random dreamer
 		if len(aliveDreamers) > 0 {
-			targetIdx := aliveDreamers[now.Unix()%int64(len(aliveDreamers))]
+			targetIdx := aliveDreamers[gameState.rng.Intn(len(aliveDreamers))]
 			char := &gameState.rooms[0].characters[targetIdx]
+			gameState.hunterPos = targetIdx + 1
 
 			damage := gameState.hunterAttack / 2
 			char.doorHP -= damage
 			if char.doorHP < 0 {
 				char.doorHP = 0
 			}
+			emitEvent(Event{Type: EventDreamerHit, Dreamer: char.name, Damage: damage, DoorHP: intPtr(char.doorHP)})
 
 			// Dreamers repair their doors slowly
 			if char.doorHP < char.doorMaxHP && char.doorHP > 0 {
@@ -235,11 +309,58 @@ func UpdateCombat(logPanel *tview.TextView) {
 				char.doorMaxHP = GetDoorHP(char.doorLevel)
 				char.doorHP = char.doorMaxHP
 				char.lastUpgradeTime = now
+				emitEvent(Event{Type: EventDreamerUpgrade, Dreamer: char.name, Level: char.doorLevel})
 			}
 		}
 	}
 }
 
+// fireGun shoots gun at the hunter if it is ready. owner is the dreamer
+// holding it, or empty for yours. It reports whether the hunter died.
+func fireGun(gun *Gun, owner string, now time.Time, logPanel *tview.TextView) bool {
+	interval := time.Duration(1000.0/gun.attackSpeed) * time.Millisecond
+	if now.Sub(gun.lastShot) < interval {
+		return false
+	}
+	gameState.hunterHP -= gun.damage
+	gun.lastShot = now
+	if gameState.hunterHP < 0 {
+		gameState.hunterHP = 0
+	}
+	emitEvent(Event{Type: EventShot, Gun: gun.name, Dreamer: owner, Damage: gun.damage, HunterHP: intPtr(gameState.hunterHP)})
+
+	if gameState.hunterHP <= 0 {
+		gameState.hunterActive = false
+		gameState.gameOver = true
+		gameState.gameWon = true
+		AddLog(logPanel, LogCombat, "[green]"+T("log.hunter_defeated")+"[white]")
+		emitEvent(Event{Type: EventGameOver, Won: boolPtr(true)})
+		return true
+	}
+	return false
+}
+
+// UpdateHunterSpawn advances the spawn timer by one second and
+// spawns a hunter once it reaches the rolled spawn delay
+func UpdateHunterSpawn(logPanel *tview.TextView) {
+	if tutorial.HoldsSpawns() {
+		return
+	}
+	gameState.hunterSpawnCounter++
+	if gameState.hunterSpawnCounter >= gameState.hunterSpawnDelay {
+		SpawnHunter(logPanel)
+		gameState.hunterSpawnCounter = 0
+		gameState.hunterSpawnDelay = rollSpawnDelay()
+	}
+}
+
+// rollSpawnDelay picks the seconds until the next spawn: the difficulty's
+// SpawnInterval, give or take a quarter, so each seed plays differently
+func rollSpawnDelay() int {
+	spread := difficulty.SpawnInterval / 4
+	return difficulty.SpawnInterval - spread + gameState.rng.Intn(2*spread+1)
+}
+
 func SpawnHunter(logPanel *tview.TextView) {
 	if !gameState.hunterActive && !gameState.gameOver {
 		gameState.hunterActive = true
@@ -247,17 +368,12 @@ func SpawnHunter(logPanel *tview.TextView) {
 		gameState.hunterMaxHP = gameState.hunterHP
 		gameState.hunterAttack = GetHunterAttack(gameState.hunterLevel)
 		gameState.hunterPos = 0
-		gameState.lastAttackTime = time.Now()
-		AddLog(logPanel, fmt.Sprintf("[red]Dream Hunter Level %d spawned![white]", gameState.hunterLevel))
+		gameState.lastAttackTime = timeNow()
+		AddLog(logPanel, LogCombat, "[red]"+T("log.hunter_spawned", gameState.hunterLevel)+"[white]")
+		emitEvent(Event{Type: EventSpawn, Level: gameState.hunterLevel, HunterHP: intPtr(gameState.hunterHP)})
 	}
 }
 
-func AddLog(logPanel *tview.TextView, message string) {
-	timestamp := time.Now().Format("15:04:05")
-	fmt.Fprintf(logPanel, "[yellow]%s[white] %s\n", timestamp, message)
-	logPanel.ScrollToEnd()
-}
-
 func DrawHPBar(current, max int, width int) string {
 	if max == 0 {
 		return ""
@@ -269,38 +385,38 @@ func DrawHPBar(current, max int, width int) string {
 	bar := "["
 	for i := 0; i < width; i++ {
 		if i < filled {
-			bar += "█"
+			bar += glyph("█", "#")
 		} else {
-			bar += "░"
+			bar += glyph("░", "-")
 		}
 	}
-	bar += "]"
+	bar += "]" + hpMark(current, max)
 	return bar
 }
 
 // GetHunterHP calculates hunter HP based on level
-// Formula: HP(L) = HP₀ × r^(L−1), HP₀=500, r=1.4
+// Formula: HP(L) = HP₀ × r^(L−1), HP₀=500, r=1.4 on Normal
 func GetHunterHP(level int) int {
-	hp0 := 500.0
-	r := 1.4
+	hp0 := difficulty.HunterHPBase
+	r := difficulty.HunterHPGrowth
 	hp := hp0 * pow(r, float64(level-1))
 	return int(hp)
 }
 
 // GetHunterAttack calculates hunter attack based on level
-// Formula: ATK(L) = ATK₀ × s^(L−1), ATK₀=50, s=1.25
+// Formula: ATK(L) = ATK₀ × s^(L−1), ATK₀=50, s=1.25 on Normal
 func GetHunterAttack(level int) int {
-	atk0 := 50.0
-	s := 1.25
+	atk0 := difficulty.HunterAttackBase
+	s := difficulty.HunterAttackGrowth
 	atk := atk0 * pow(s, float64(level-1))
 	return int(atk)
 }
 
 // GetDoorHP calculates door HP based on level
-// Formula: HP(L) = HP₀ + a × (L−1), HP₀=2000, a=300
+// Formula: HP(L) = HP₀ + a × (L−1), HP₀=2000, a=300 on Normal
 func GetDoorHP(level int) int {
-	hp0 := 2000
-	a := 300
+	hp0 := difficulty.DoorHPBase
+	a := difficulty.DoorHPPerLevel
 	return hp0 + a*(level-1)
 }
 
@@ -361,59 +477,6 @@ func GetGunPrice(gunCount int) int {
 	return price
 }
 
-func BuyItem(itemIndex int, logPanel *tview.TextView) {
-	items := GetAvailableItems()
-	if itemIndex < 0 || itemIndex >= len(items) {
-		AddLog(logPanel, "[red]Invalid item![white]")
-		return
-	}
-
-	item := items[itemIndex]
-
-	// Check if can afford
-	if !CanAffordItem(item) {
-		AddLog(logPanel, "[red]Not enough resources![white]")
-		return
-	}
-
-	// Deduct costs
-	gameState.coins -= item.costCoins
-	gameState.diamonds -= item.costDiamonds
-
-	// Apply item effect
-	switch item.itemType {
-	case "bed":
-		gameState.bedLevel++
-		AddLog(logPanel, fmt.Sprintf("[green]Bed upgraded to level %d! (+%.0f coins/s)[white]", gameState.bedLevel, item.production))
-	case "door":
-		gameState.doorLevel++
-		gameState.doorMaxHP = GetDoorHP(gameState.doorLevel)
-		gameState.doorHP = gameState.doorMaxHP
-		AddLog(logPanel, fmt.Sprintf("[green]Door upgraded to level %d! (HP: %d)[white]", gameState.doorLevel, gameState.doorMaxHP))
-	case "playbox":
-		gameState.playboxLevel++
-		AddLog(logPanel, fmt.Sprintf("[cyan]Playbox upgraded to level %d! (+%.0f diamonds/s)[white]", gameState.playboxLevel, item.production))
-	case "trap":
-		gameState.playerDefense += 5
-		gameState.playerMaxDefense += 5
-		AddLog(logPanel, "[green]Trap installed! Defense +5[white]")
-	case "guard":
-		gameState.playerDefense += 10
-		gameState.playerMaxDefense += 10
-		AddLog(logPanel, "[green]Guard hired! Defense +10[white]")
-	case "gun":
-		gun := Gun{
-			name:        item.name,
-			level:       1,
-			damage:      item.damage,
-			attackSpeed: item.attackSpeed,
-			lastShot:    time.Now(),
-		}
-		gameState.guns = append(gameState.guns, gun)
-		AddLog(logPanel, fmt.Sprintf("[yellow]%s purchased! Damage: %d, Speed: %.1f/s[white]", item.name, item.damage, item.attackSpeed))
-	}
-}
-
 func GetGameState() *GameState {
 	return gameState
 }
@@ -423,12 +486,11 @@ func GetAvailableItemsByCategory(category int) []Item {
 
 	switch category {
 	case 0: // Coins category
-		// Bed: levels 1-10, price = 25 * 2^(current_level-1)
+		// Bed: levels 1-10
 		if gameState.bedLevel < 10 {
 			nextLevel := gameState.bedLevel + 1
-			costShift := uint(gameState.bedLevel - 1)
-			coinCost := 25 * (int(1) << costShift)
-			diamondCost := 0
+			coinCost := levelPrice("Bed", gameState.bedLevel)
+			diamondCost := BigNum(0)
 			prodShift := uint(nextLevel - 1)
 			production := float64(int(1) << prodShift)
 
@@ -439,15 +501,14 @@ func GetAvailableItemsByCategory(category int) []Item {
 				costCoins:    coinCost,
 				costDiamonds: diamondCost,
 				production:   production,
-				description:  fmt.Sprintf("+%.0f coins/s", production),
+				description:  T("desc.coins_per_s", FormatNumber(production)),
 				itemType:     "bed",
 			})
 		}
 
-		// Door: levels 1-10, price = 16 * 2^(current_level-1)
+		// Door: levels 1-10
 		if gameState.doorLevel < 10 {
-			costShift := uint(gameState.doorLevel - 1)
-			coinCost := 16 * (int(1) << costShift)
+			coinCost := levelPrice("Door", gameState.doorLevel)
 
 			items = append(items, Item{
 				name:         "Door",
@@ -456,17 +517,16 @@ func GetAvailableItemsByCategory(category int) []Item {
 				costCoins:    coinCost,
 				costDiamonds: 0,
 				production:   0,
-				description:  "+50 HP",
+				description:  T("desc.door_hp"),
 				itemType:     "door",
 			})
 		}
 
 	case 1: // Diamonds category
-		// Playbox: levels 1-10, price = 200 * 2^(n-1)
+		// Playbox: levels 1-10
 		if gameState.playboxLevel < 10 {
 			nextLevel := gameState.playboxLevel + 1
-			costShift := uint(nextLevel - 1)
-			coinCost := 200 * (int(1) << costShift)
+			coinCost := levelPrice("Playbox", gameState.playboxLevel)
 			prodShift := uint(nextLevel - 1)
 			production := float64(int(1) << prodShift)
 
@@ -477,7 +537,7 @@ func GetAvailableItemsByCategory(category int) []Item {
 				costCoins:    coinCost,
 				costDiamonds: 0,
 				production:   production,
-				description:  fmt.Sprintf("+%.0f diamonds/s", production),
+				description:  T("desc.diamonds_per_s", FormatNumber(production)),
 				itemType:     "playbox",
 			})
 		}
@@ -488,9 +548,9 @@ func GetAvailableItemsByCategory(category int) []Item {
 			currentLevel: 0,
 			maxLevel:     999,
 			costCoins:    0,
-			costDiamonds: 5,
+			costDiamonds: scalePrice(5),
 			production:   0,
-			description:  "+5 defense",
+			description:  T("desc.defense", 5),
 			itemType:     "trap",
 		})
 
@@ -500,26 +560,25 @@ func GetAvailableItemsByCategory(category int) []Item {
 			currentLevel: 0,
 			maxLevel:     999,
 			costCoins:    0,
-			costDiamonds: 10,
+			costDiamonds: scalePrice(10),
 			production:   0,
-			description:  "+10 defense",
+			description:  T("desc.defense", 10),
 			itemType:     "guard",
 		})
 
 	case 2: // Guns category
 		gunCount := len(gameState.guns)
-		gunPrice := GetGunPrice(gunCount)
 		gunDamage := GetGunDamage(gunCount + 1)
 
 		items = append(items, Item{
 			name:         "Pistol",
 			currentLevel: gunCount,
 			maxLevel:     999,
-			costCoins:    gunPrice,
+			costCoins:    levelPrice("Pistol", gunCount),
 			costDiamonds: 0,
 			damage:       gunDamage,
 			attackSpeed:  1.0,
-			description:  fmt.Sprintf("%ddmg 1.0atk/s", gunDamage),
+			description:  T("desc.gun", gunDamage, FormatDecimal(1.0, 1)),
 			itemType:     "gun",
 		})
 
@@ -527,11 +586,11 @@ func GetAvailableItemsByCategory(category int) []Item {
 			name:         "Rifle",
 			currentLevel: 0,
 			maxLevel:     999,
-			costCoins:    150,
-			costDiamonds: 5,
+			costCoins:    scalePrice(150),
+			costDiamonds: scalePrice(5),
 			damage:       15,
 			attackSpeed:  0.5,
-			description:  "15dmg 0.5atk/s",
+			description:  T("desc.gun", 15, FormatDecimal(0.5, 1)),
 			itemType:     "gun",
 		})
 
@@ -539,11 +598,11 @@ func GetAvailableItemsByCategory(category int) []Item {
 			name:         "Shotgun",
 			currentLevel: 0,
 			maxLevel:     999,
-			costCoins:    200,
-			costDiamonds: 10,
+			costCoins:    scalePrice(200),
+			costDiamonds: scalePrice(10),
 			damage:       30,
 			attackSpeed:  0.3,
-			description:  "30dmg 0.3atk/s",
+			description:  T("desc.gun", 30, FormatDecimal(0.3, 1)),
 			itemType:     "gun",
 		})
 
@@ -551,11 +610,11 @@ func GetAvailableItemsByCategory(category int) []Item {
 			name:         "Machine Gun",
 			currentLevel: 0,
 			maxLevel:     999,
-			costCoins:    300,
-			costDiamonds: 20,
+			costCoins:    scalePrice(300),
+			costDiamonds: scalePrice(20),
 			damage:       8,
 			attackSpeed:  3.0,
-			description:  "8dmg 3.0atk/s",
+			description:  T("desc.gun", 8, FormatDecimal(3.0, 1)),
 			itemType:     "gun",
 		})
 
@@ -563,13 +622,16 @@ func GetAvailableItemsByCategory(category int) []Item {
 			name:         "Sniper",
 			currentLevel: 0,
 			maxLevel:     999,
-			costCoins:    500,
-			costDiamonds: 50,
+			costCoins:    scalePrice(500),
+			costDiamonds: scalePrice(50),
 			damage:       100,
 			attackSpeed:  0.2,
-			description:  "100dmg 0.2atk/s",
+			description:  T("desc.gun", 100, FormatDecimal(0.2, 1)),
 			itemType:     "gun",
 		})
+
+	case autoBuyerCategory:
+		items = autoBuyerItems()
 	}
 
 	return items
@@ -578,7 +640,7 @@ func GetAvailableItemsByCategory(category int) []Item {
 func BuyItemByCategory(itemIndex int, category int, logPanel *tview.TextView) {
 	items := GetAvailableItemsByCategory(category)
 	if itemIndex < 0 || itemIndex >= len(items) {
-		AddLog(logPanel, "[red]Invalid item![white]")
+		AddLog(logPanel, LogEconomy, "[red]"+T("log.invalid_item")+"[white]")
 		return
 	}
 
@@ -586,203 +648,211 @@ func BuyItemByCategory(itemIndex int, category int, logPanel *tview.TextView) {
 
 	// Check if can afford
 	if !CanAffordItem(item) {
-		AddLog(logPanel, "[red]Not enough resources![white]")
+		AddLog(logPanel, LogEconomy, "[red]"+T("log.not_enough_resources")+"[white]")
+		return
+	}
+
+	AddLog(logPanel, LogEconomy, purchaseItem(item))
+	updateItemsPanelList()
+}
+
+// BuyMax as a batch size buys as many as can be afforded
+const BuyMax = -1
+
+// maxBatch caps how many items one BuyMax purchase buys
+const maxBatch = 100
+
+// BuyItemsByCategory buys count of a shop item in one go. The batch is
+// all or nothing: if its total cost is not affordable nothing is bought.
+// One summary line is logged for the whole batch.
+func BuyItemsByCategory(itemIndex, category, count int, logPanel *tview.TextView) {
+	if count == 0 || count == 1 {
+		BuyItemByCategory(itemIndex, category, logPanel)
+		return
+	}
+	items := GetAvailableItemsByCategory(category)
+	if itemIndex < 0 || itemIndex >= len(items) {
+		AddLog(logPanel, LogEconomy, "[red]"+T("log.invalid_item")+"[white]")
 		return
 	}
 
+	n, coins, diamonds := PlanPurchase(itemIndex, category, count)
+	if n == 0 || gameState.coins < coins || gameState.diamonds < diamonds {
+		AddLog(logPanel, LogEconomy, "[red]"+T("log.not_enough_resources")+"[white]")
+		return
+	}
+	if item := items[itemIndex]; fixedPrice(item) {
+		purchaseStack(item, n)
+	} else {
+		for i := 0; i < n; i++ {
+			purchaseItem(GetAvailableItemsByCategory(category)[itemIndex])
+		}
+	}
+	AddLog(logPanel, LogEconomy, "[green]"+T("log.bought_batch", glyph("×", "x")+strconv.Itoa(n), itemName(items[itemIndex].name), costString(coins, diamonds))+"[white]")
+	updateItemsPanelList()
+}
+
+// PlanPurchase works out a batch of count purchases of a shop item
+// (BuyMax for as many as can be afforded, at most maxBatch) along the
+// item's escalating price curve. It returns how many would be bought,
+// stopping at the max level, and their total cost.
+func PlanPurchase(itemIndex, category, count int) (n int, coins, diamonds BigNum) {
+	items := GetAvailableItemsByCategory(category)
+	if itemIndex < 0 || itemIndex >= len(items) {
+		return 0, 0, 0
+	}
+	item := items[itemIndex]
+
+	// Flat-priced items can be bought in any number, so work out max directly
+	if count == BuyMax && fixedPrice(item) {
+		n = maxBatch
+		if item.costCoins > 0 {
+			n = min(n, int(gameState.coins/item.costCoins))
+		}
+		if item.costDiamonds > 0 {
+			n = min(n, int(gameState.diamonds/item.costDiamonds))
+		}
+		return n, item.costCoins * BigNum(n), item.costDiamonds * BigNum(n)
+	}
+
+	for ; count == BuyMax && n < maxBatch || n < count; n++ {
+		c, d, ok := priceAfter(item, n)
+		if !ok {
+			break
+		}
+		if count == BuyMax && (gameState.coins < coins+c || gameState.diamonds < diamonds+d) {
+			break
+		}
+		coins += c
+		diamonds += d
+	}
+	return n, coins, diamonds
+}
+
+// CanAffordBatch reports whether a batch of count of a shop item (BuyMax
+// for as many as can be afforded) can be bought right now
+func CanAffordBatch(itemIndex, category, count int) bool {
+	n, coins, diamonds := PlanPurchase(itemIndex, category, count)
+	return n > 0 && gameState.coins >= coins && gameState.diamonds >= diamonds
+}
+
+// levelPrice is the price in coins of the Bed, Door or Playbox upgrade
+// bought at level, or of the Pistol bought with level guns owned. The
+// shop, batches, upgrades, selling and remote players all price with it.
+func levelPrice(name string, level int) BigNum {
+	switch name {
+	case "Bed":
+		return scalePrice(25 * (int(1) << uint(level-1))) // 25, 50, 100...
+	case "Door":
+		return scalePrice(16 * (int(1) << uint(level-1))) // 16, 32, 64...
+	case "Playbox":
+		return scalePrice(200 * (int(1) << uint(level))) // 200, 400, 800...
+	case "Pistol":
+		return scalePrice(GetGunPrice(level))
+	}
+	return 0
+}
+
+// levelled reports whether name is priced by levelPrice
+func levelled(name string) bool {
+	return name == "Bed" || name == "Door" || name == "Playbox" || name == "Pistol"
+}
+
+// fixedPrice reports whether buying item leaves its price unchanged
+func fixedPrice(item Item) bool {
+	return item.maxLevel >= 999 && item.name != "Pistol"
+}
+
+// priceAfter is the price of item after k more purchases of it, following
+// the same curves as GetAvailableItemsByCategory. It fails once k
+// purchases would reach the max level.
+func priceAfter(item Item, k int) (coins, diamonds BigNum, ok bool) {
+	level := item.currentLevel + k
+	if item.maxLevel < 999 && level >= item.maxLevel {
+		return 0, 0, false
+	}
+	if levelled(item.name) {
+		return levelPrice(item.name, level), 0, true
+	}
+	return item.costCoins, item.costDiamonds, true
+}
+
+// purchaseStack pays for n of a flat-priced item at once and applies them,
+// with one purchase event carrying the count
+func purchaseStack(item Item, n int) {
+	gameState.coins -= item.costCoins * BigNum(n)
+	gameState.diamonds -= item.costDiamonds * BigNum(n)
+	switch item.itemType {
+	case "trap":
+		gameState.playerDefense += 5 * n
+		gameState.playerMaxDefense += 5 * n
+	case "guard":
+		gameState.playerDefense += 10 * n
+		gameState.playerMaxDefense += 10 * n
+	case "gun":
+		for i := 0; i < n; i++ {
+			gameState.guns = append(gameState.guns, Gun{
+				name:         item.name,
+				level:        1,
+				damage:       item.damage,
+				attackSpeed:  item.attackSpeed,
+				lastShot:     timeNow(),
+				paidCoins:    item.costCoins,
+				paidDiamonds: item.costDiamonds,
+			})
+		}
+	}
+	emitEvent(Event{Type: EventPurchase, Item: item.name, Count: n, Coins: float64(item.costCoins) * float64(n), Diamonds: float64(item.costDiamonds) * float64(n)})
+}
+
+// purchaseItem pays for item and applies it, returning the log line
+// describing the purchase
+func purchaseItem(item Item) string {
 	// Deduct costs
 	gameState.coins -= item.costCoins
 	gameState.diamonds -= item.costDiamonds
 
 	// Apply item effect
+	msg := ""
 	switch item.itemType {
 	case "bed":
 		gameState.bedLevel++
-		AddLog(logPanel, fmt.Sprintf("[green]Bed upgraded to level %d! (+%.0f coins/s)[white]", gameState.bedLevel, item.production))
+		msg = "[green]" + T("log.bed_upgraded_income", gameState.bedLevel, FormatNumber(item.production)) + "[white]"
 	case "door":
 		gameState.doorLevel++
 		gameState.doorMaxHP = GetDoorHP(gameState.doorLevel)
 		gameState.doorHP = gameState.doorMaxHP
-		AddLog(logPanel, fmt.Sprintf("[green]Door upgraded to level %d! (HP: %d)[white]", gameState.doorLevel, gameState.doorMaxHP))
+		msg = "[green]" + T("log.door_upgraded", gameState.doorLevel, FormatNumber(float64(gameState.doorMaxHP))) + "[white]"
 	case "playbox":
 		gameState.playboxLevel++
-		AddLog(logPanel, fmt.Sprintf("[cyan]Playbox upgraded to level %d! (+%.0f diamonds/s)[white]", gameState.playboxLevel, item.production))
+		msg = "[cyan]" + T("log.playbox_upgraded_income", gameState.playboxLevel, FormatNumber(item.production)) + "[white]"
 	case "trap":
 		gameState.playerDefense += 5
 		gameState.playerMaxDefense += 5
-		AddLog(logPanel, "[green]Trap installed! Defense +5[white]")
+		msg = "[green]" + T("log.trap_installed") + "[white]"
 	case "guard":
 		gameState.playerDefense += 10
 		gameState.playerMaxDefense += 10
-		AddLog(logPanel, "[green]Guard hired! Defense +10[white]")
+		msg = "[green]" + T("log.guard_hired") + "[white]"
 	case "gun":
 		gun := Gun{
-			name:        item.name,
-			level:       1,
-			damage:      item.damage,
-			attackSpeed: item.attackSpeed,
-			lastShot:    time.Now(),
+			name:         item.name,
+			level:        1,
+			damage:       item.damage,
+			attackSpeed:  item.attackSpeed,
+			lastShot:     timeNow(),
+			paidCoins:    item.costCoins,
+			paidDiamonds: item.costDiamonds,
 		}
 		gameState.guns = append(gameState.guns, gun)
-		AddLog(logPanel, fmt.Sprintf("[yellow]%s purchased! Damage: %d, Speed: %.1f/s[white]", item.name, item.damage, item.attackSpeed))
-	}
-
-	updateItemsPanelList()
-}
-
-func GetAvailableItems() []Item {
-	items := []Item{}
-
-	// Bed: levels 1-10, price = 25 * 2^(current_level-1)
-	// Production: 1, 2, 4, 8, 16, 32, 64, 128, 256, 512 coins/s
-	if gameState.bedLevel < 10 {
-		nextLevel := gameState.bedLevel + 1
-		costShift := uint(gameState.bedLevel - 1)
-		coinCost := 25 * (int(1) << costShift)
-		diamondCost := 0
-		prodShift := uint(nextLevel - 1)
-		production := float64(int(1) << prodShift)
-
-		items = append(items, Item{
-			name:         "Bed",
-			currentLevel: gameState.bedLevel,
-			maxLevel:     10,
-			costCoins:    coinCost,
-			costDiamonds: diamondCost,
-			production:   production,
-			description:  fmt.Sprintf("Lv%d→%d: +%.0f coins/s", gameState.bedLevel, nextLevel, production),
-			itemType:     "bed",
-		})
-	}
-
-	// Door: levels 1-10, price = 16 * 2^(current_level-1)
-	if gameState.doorLevel < 10 {
-		nextLevel := gameState.doorLevel + 1
-		costShift := uint(gameState.doorLevel - 1)
-		coinCost := 16 * (int(1) << costShift)
-
-		items = append(items, Item{
-			name:         "Door",
-			currentLevel: gameState.doorLevel,
-			maxLevel:     10,
-			costCoins:    coinCost,
-			costDiamonds: 0,
-			production:   0,
-			description:  fmt.Sprintf("Lv%d→%d: +50 HP", gameState.doorLevel, nextLevel),
-			itemType:     "door",
-		})
+		msg = "[yellow]" + T("log.gun_purchased", itemName(item.name), item.damage, FormatDecimal(item.attackSpeed, 1)) + "[white]"
+	case "autobuyer":
+		unlockAutoBuyer(item.name)
+		msg = "[green]" + T("log.autobuyer_unlocked", itemName(item.name)) + "[white]"
 	}
 
-	// Playbox: levels 1-10, price = 200 * 2^(n-1)
-	// Production: 1, 2, 4, 8, 16, 32, 64, 128, 256, 512 diamonds/s
-	if gameState.playboxLevel < 10 {
-		nextLevel := gameState.playboxLevel + 1
-		costShift := uint(nextLevel - 1)
-		coinCost := 200 * (int(1) << costShift)
-		prodShift := uint(nextLevel - 1)
-		production := float64(int(1) << prodShift)
-
-		items = append(items, Item{
-			name:         "Playbox",
-			currentLevel: gameState.playboxLevel,
-			maxLevel:     10,
-			costCoins:    coinCost,
-			costDiamonds: 0,
-			production:   production,
-			description:  fmt.Sprintf("Lv%d→%d: +%.0f diamonds/s", gameState.playboxLevel, nextLevel, production),
-			itemType:     "playbox",
-		})
-	}
-
-	// Trap and Guard - one-time purchases
-	items = append(items, Item{
-		name:         "Trap",
-		currentLevel: 0,
-		maxLevel:     999,
-		costCoins:    0,
-		costDiamonds: 5,
-		production:   0,
-		description:  "+5 defense",
-		itemType:     "trap",
-	})
-
-	items = append(items, Item{
-		name:         "Guard",
-		currentLevel: 0,
-		maxLevel:     999,
-		costCoins:    0,
-		costDiamonds: 10,
-		production:   0,
-		description:  "+10 defense",
-		itemType:     "guard",
-	})
-
-	// Guns - various weapons
-	gunCount := len(gameState.guns)
-	gunPrice := GetGunPrice(gunCount)
-	gunDamage := GetGunDamage(gunCount + 1)
-
-	items = append(items, Item{
-		name:         "Pistol",
-		currentLevel: gunCount,
-		maxLevel:     999,
-		costCoins:    gunPrice,
-		costDiamonds: 0,
-		damage:       gunDamage,
-		attackSpeed:  1.0,
-		description:  fmt.Sprintf("%d dmg, 1.0 atk/s", gunDamage),
-		itemType:     "gun",
-	})
-
-	items = append(items, Item{
-		name:         "Rifle",
-		currentLevel: 0,
-		maxLevel:     999,
-		costCoins:    150,
-		costDiamonds: 5,
-		damage:       15,
-		attackSpeed:  0.5,
-		description:  "15 dmg, 0.5 atk/s",
-		itemType:     "gun",
-	})
-
-	items = append(items, Item{
-		name:         "Shotgun",
-		currentLevel: 0,
-		maxLevel:     999,
-		costCoins:    200,
-		costDiamonds: 10,
-		damage:       30,
-		attackSpeed:  0.3,
-		description:  "30 dmg, 0.3 atk/s",
-		itemType:     "gun",
-	})
-
-	items = append(items, Item{
-		name:         "Machine Gun",
-		currentLevel: 0,
-		maxLevel:     999,
-		costCoins:    300,
-		costDiamonds: 20,
-		damage:       8,
-		attackSpeed:  3.0,
-		description:  "8 dmg, 3.0 atk/s",
-		itemType:     "gun",
-	})
-
-	items = append(items, Item{
-		name:         "Sniper",
-		currentLevel: 0,
-		maxLevel:     999,
-		costCoins:    500,
-		costDiamonds: 50,
-		damage:       100,
-		attackSpeed:  0.2,
-		description:  "100 dmg, 0.2 atk/s",
-		itemType:     "gun",
-	})
-
-	return items
+	emitEvent(Event{Type: EventPurchase, Item: item.name, Coins: float64(item.costCoins), Diamonds: float64(item.costDiamonds)})
+	return msg
 }
 
 func CanAffordItem(item Item) bool {
@@ -791,6 +861,29 @@ func CanAffordItem(item Item) bool {
 	return hasCoins && hasDiamonds
 }
 
+// AffordETA is how many seconds of current income it takes until coins
+// and diamonds are both on hand. It fails when the income of a missing
+// resource is zero.
+func AffordETA(coins, diamonds BigNum) (float64, bool) {
+	eta := 0.0
+	for _, r := range []struct {
+		have, need BigNum
+		perS       float64
+	}{
+		{gameState.coins, coins, gameState.coinsPerS},
+		{gameState.diamonds, diamonds, gameState.diamPerS},
+	} {
+		if r.have >= r.need {
+			continue
+		}
+		if r.perS <= 0 {
+			return 0, false
+		}
+		eta = math.Max(eta, math.Ceil(float64(r.need-r.have)/r.perS))
+	}
+	return eta, true
+}
+
 func GetItemColor(item Item) string {
 	// Check if owned (at max level for upgradeable items)
 	if item.currentLevel >= item.maxLevel && item.maxLevel < 999 {
@@ -811,24 +904,31 @@ func updateItemsPanelList() {
 	items := []string{}
 
 	// Add door
-	items = append(items, fmt.Sprintf("Door Lv%d (HP:%d)", gameState.doorLevel, gameState.doorMaxHP))
+	items = append(items, T("owned.door", gameState.doorLevel, FormatNumber(float64(gameState.doorMaxHP))))
 
 	// Add bed if purchased
 	if gameState.bedLevel > 0 {
-		items = append(items, fmt.Sprintf("Bed Lv%d (+%.0f/s)", gameState.bedLevel, gameState.coinsPerS))
+		items = append(items, T("owned.bed", gameState.bedLevel, FormatNumber(gameState.coinsPerS)))
 	}
 
 	// Add playbox if purchased
 	if gameState.playboxLevel > 0 {
-		items = append(items, fmt.Sprintf("Playbox Lv%d (+%.0f/s)", gameState.playboxLevel, gameState.diamPerS))
+		items = append(items, T("owned.playbox", gameState.playboxLevel, FormatNumber(gameState.diamPerS)))
 	}
 
 	// Add defense
-	items = append(items, fmt.Sprintf("Defense: %d", gameState.playerMaxDefense))
+	items = append(items, T("owned.defense", FormatNumber(float64(gameState.playerMaxDefense))))
 
 	// Add guns
 	for _, gun := range gameState.guns {
-		items = append(items, fmt.Sprintf("%s (D:%d S:%.1f)", gun.name, gun.damage, gun.attackSpeed))
+		items = append(items, T("owned.gun", itemName(gun.name), gun.damage, FormatDecimal(gun.attackSpeed, 1)))
+	}
+
+	// Add auto-buyers last; autoBuyerRow relies on it
+	for i, buyer := range gameState.autoBuyers {
+		if buyer.owned {
+			items = append(items, describeAutoBuyer(i))
+		}
 	}
 
 	gameState.itemsPanelItems = items
@@ -842,6 +942,13 @@ func MoveItemSelection(direction int) {
 	}
 }
 
+// SelectItem selects a row of the items panel
+func SelectItem(index int) {
+	if index >= 0 && index < len(gameState.itemsPanelItems) {
+		gameState.itemsPanelSelected = index
+	}
+}
+
 // UpgradeSelectedItem upgrades the selected item in items panel
 func UpgradeSelectedItem(logPanel *tview.TextView) {
 	if gameState.itemsPanelSelected < 0 || gameState.itemsPanelSelected >= len(gameState.itemsPanelItems) {
@@ -855,21 +962,21 @@ func UpgradeSelectedItem(logPanel *tview.TextView) {
 	if gameState.itemsPanelSelected == itemOffset {
 		// Door
 		if gameState.doorLevel < 10 {
-			costShift := uint(gameState.doorLevel - 1)
-			coinCost := 16 * (int(1) << costShift)
+			coinCost := levelPrice("Door", gameState.doorLevel)
 
 			if gameState.coins >= coinCost {
 				gameState.coins -= coinCost
 				gameState.doorLevel++
 				gameState.doorMaxHP = GetDoorHP(gameState.doorLevel)
 				gameState.doorHP = gameState.doorMaxHP
-				AddLog(logPanel, fmt.Sprintf("[green]Door upgraded to level %d! (HP: %d)[white]", gameState.doorLevel, gameState.doorMaxHP))
+				AddLog(logPanel, LogEconomy, "[green]"+T("log.door_upgraded", gameState.doorLevel, FormatNumber(float64(gameState.doorMaxHP)))+"[white]")
+				emitEvent(Event{Type: EventUpgrade, Item: "Door", Level: gameState.doorLevel, Coins: float64(coinCost)})
 				updateItemsPanelList()
 			} else {
-				AddLog(logPanel, "[red]Not enough coins![white]")
+				AddLog(logPanel, LogEconomy, "[red]"+T("log.not_enough_coins")+"[white]")
 			}
 		} else {
-			AddLog(logPanel, "[yellow]Door is at max level![white]")
+			AddLog(logPanel, LogEconomy, "[yellow]"+T("log.max_level", itemName("Door"))+"[white]")
 		}
 		return
 	}
@@ -879,19 +986,19 @@ func UpgradeSelectedItem(logPanel *tview.TextView) {
 	if gameState.bedLevel > 0 {
 		if gameState.itemsPanelSelected == itemOffset {
 			if gameState.bedLevel < 10 {
-				costShift := uint(gameState.bedLevel - 1)
-				coinCost := 25 * (int(1) << costShift)
+				coinCost := levelPrice("Bed", gameState.bedLevel)
 
 				if gameState.coins >= coinCost {
 					gameState.coins -= coinCost
 					gameState.bedLevel++
-					AddLog(logPanel, fmt.Sprintf("[green]Bed upgraded to level %d![white]", gameState.bedLevel))
+					AddLog(logPanel, LogEconomy, "[green]"+T("log.bed_upgraded", gameState.bedLevel)+"[white]")
+					emitEvent(Event{Type: EventUpgrade, Item: "Bed", Level: gameState.bedLevel, Coins: float64(coinCost)})
 					updateItemsPanelList()
 				} else {
-					AddLog(logPanel, "[red]Not enough coins![white]")
+					AddLog(logPanel, LogEconomy, "[red]"+T("log.not_enough_coins")+"[white]")
 				}
 			} else {
-				AddLog(logPanel, "[yellow]Bed is at max level![white]")
+				AddLog(logPanel, LogEconomy, "[yellow]"+T("log.max_level", itemName("Bed"))+"[white]")
 			}
 			return
 		}
@@ -902,20 +1009,19 @@ func UpgradeSelectedItem(logPanel *tview.TextView) {
 	if gameState.playboxLevel > 0 {
 		if gameState.itemsPanelSelected == itemOffset {
 			if gameState.playboxLevel < 10 {
-				nextLevel := gameState.playboxLevel + 1
-				costShift := uint(nextLevel - 1)
-				coinCost := 200 * (int(1) << costShift)
+				coinCost := levelPrice("Playbox", gameState.playboxLevel)
 
 				if gameState.coins >= coinCost {
 					gameState.coins -= coinCost
 					gameState.playboxLevel++
-					AddLog(logPanel, fmt.Sprintf("[green]Playbox upgraded to level %d![white]", gameState.playboxLevel))
+					AddLog(logPanel, LogEconomy, "[green]"+T("log.playbox_upgraded", gameState.playboxLevel)+"[white]")
+					emitEvent(Event{Type: EventUpgrade, Item: "Playbox", Level: gameState.playboxLevel, Coins: float64(coinCost)})
 					updateItemsPanelList()
 				} else {
-					AddLog(logPanel, "[red]Not enough coins![white]")
+					AddLog(logPanel, LogEconomy, "[red]"+T("log.not_enough_coins")+"[white]")
 				}
 			} else {
-				AddLog(logPanel, "[yellow]Playbox is at max level![white]")
+				AddLog(logPanel, LogEconomy, "[yellow]"+T("log.max_level", itemName("Playbox"))+"[white]")
 			}
 			return
 		}
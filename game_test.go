@@ -0,0 +1,63 @@
+package main
+
+import "testing"
+
+// findItem finds the shop item called name
+func findItem(t *testing.T, name string) (category, index int, item Item) {
+	t.Helper()
+	for category := 0; category < shopCategories; category++ {
+		for i, item := range GetAvailableItemsByCategory(category) {
+			if item.name == name {
+				return category, i, item
+			}
+		}
+	}
+	t.Fatalf("%s is not in the shop", name)
+	return 0, 0, Item{}
+}
+
+func TestPriceAfterMatchesShop(t *testing.T) {
+	SetDifficulty(difficultyPresets["normal"])
+	for _, name := range []string{"Bed", "Door", "Playbox", "Pistol"} {
+		InitGameWithSeed(1)
+		gameState.coins = 1e9
+		_, _, first := findItem(t, name)
+		for k := 0; k < 4; k++ {
+			category, index, item := findItem(t, name)
+			if want, _, _ := priceAfter(first, k); item.costCoins != want {
+				t.Errorf("%s after %d buys: shop asks %v, priceAfter says %v", name, k, item.costCoins, want)
+			}
+			BuyItemByCategory(index, category, nil)
+		}
+	}
+}
+
+func TestPlanPurchase(t *testing.T) {
+	SetDifficulty(difficultyPresets["normal"])
+	tests := []struct {
+		name         string
+		item         string
+		coins        BigNum
+		diamonds     BigNum
+		count        int
+		wantN        int
+		wantCoins    BigNum
+		wantDiamonds BigNum
+	}{
+		{"batch of 3", "Bed", 1000, 0, 3, 3, 25 + 50 + 100, 0},
+		{"max affordable", "Bed", 100, 0, BuyMax, 2, 25 + 50, 0},
+		{"stops at max level", "Bed", 1e9, 0, 20, 9, 25 * 511, 0},
+		{"max on a gun curve", "Pistol", 30, 0, BuyMax, 2, 8 + 16, 0},
+		{"max at a flat price", "Trap", 0, 12, BuyMax, 2, 0, 10},
+		{"max is capped", "Trap", 0, 1e9, BuyMax, maxBatch, 0, 5 * maxBatch},
+	}
+	for _, tt := range tests {
+		InitGameWithSeed(1)
+		gameState.coins, gameState.diamonds = tt.coins, tt.diamonds
+		category, index, _ := findItem(t, tt.item)
+		n, coins, diamonds := PlanPurchase(index, category, tt.count)
+		if n != tt.wantN || coins != tt.wantCoins || diamonds != tt.wantDiamonds {
+			t.Errorf("%s %s: got %d for %vc %vd, want %d for %vc %vd", tt.item, tt.name, n, coins, diamonds, tt.wantN, tt.wantCoins, tt.wantDiamonds)
+		}
+	}
+}
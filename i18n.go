@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Locale is a message catalog plus the number conventions of a language
+type Locale struct {
+	Name     string
+	Messages map[string]string // message key -> fmt template
+	Plural   func(n float64) string
+	Decimal  string   // decimal separator
+	Suffixes []string // number suffixes for 10^0, 10^3, 10^6, ...
+}
+
+// locales are the available catalogs by language code
+var locales = map[string]*Locale{
+	"en": &localeEN,
+	"id": &localeID,
+}
+
+// locale is the language player-facing text is shown in
+var locale = locales["en"]
+
+// LocaleNames returns the available language codes in sorted order
+func LocaleNames() []string {
+	names := []string{}
+	for name := range locales {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetLocale switches the language. Besides bare codes like "id" it
+// accepts POSIX locale names like "id_ID.UTF-8".
+func SetLocale(name string) error {
+	code := localeCode(name)
+	l, ok := locales[code]
+	if !ok {
+		return fmt.Errorf("unknown language %q (want %s)", name, strings.Join(LocaleNames(), ", "))
+	}
+	locale = l
+	return nil
+}
+
+// validateLang accepts "auto" or an available language
+func validateLang(name string) error {
+	if name == "auto" || locales[localeCode(name)] != nil {
+		return nil
+	}
+	return fmt.Errorf("unknown language %q (want auto, %s)", name, strings.Join(LocaleNames(), ", "))
+}
+
+// SelectLocale applies a --lang or config value; "auto" follows the
+// environment
+func SelectLocale(name string) error {
+	if name == "auto" || name == "" {
+		name = DetectLocale()
+	}
+	return SetLocale(name)
+}
+
+// DetectLocale picks a language from LC_ALL, LC_MESSAGES or LANG, in that
+// order of precedence, falling back to English
+func DetectLocale() string {
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := os.Getenv(env); value != "" {
+			if code := localeCode(value); locales[code] != nil {
+				return code
+			}
+			break
+		}
+	}
+	return "en"
+}
+
+// localeCode reduces "id_ID.UTF-8" to "id"
+func localeCode(name string) string {
+	if i := strings.IndexAny(name, "_.@-"); i >= 0 {
+		name = name[:i]
+	}
+	return strings.ToLower(name)
+}
+
+// T formats the message key in the current language. Keys missing from
+// the catalog fall back to English, then to the key itself.
+func T(key string, args ...any) string {
+	template, ok := locale.Messages[key]
+	if !ok {
+		if template, ok = localeEN.Messages[key]; !ok {
+			template = key
+		}
+	}
+	if len(args) == 0 {
+		return template
+	}
+	return fmt.Sprintf(template, args...)
+}
+
+// Tn is T for a message that depends on the count n. It looks up
+// key.one or key.other following the language's plural rule.
+func Tn(key string, n float64, args ...any) string {
+	form := key + "." + locale.Plural(n)
+	if _, ok := locale.Messages[form]; !ok {
+		form = key + ".other"
+	}
+	return T(form, args...)
+}
+
+// FormatDecimal formats v with prec decimals and the locale's separator
+func FormatDecimal(v float64, prec int) string {
+	return localizeDecimal(strconv.FormatFloat(v, 'f', prec, 64))
+}
+
+// localizeDecimal swaps the '.' of a formatted number for the locale's
+// decimal separator
+func localizeDecimal(s string) string {
+	if locale.Decimal == "." {
+		return s
+	}
+	return strings.Replace(s, ".", locale.Decimal, 1)
+}
+
+// pluralOneOther is the English rule: exactly 1 is "one"
+func pluralOneOther(n float64) string {
+	if n == 1 {
+		return "one"
+	}
+	return "other"
+}
+
+// pluralOther is for languages without grammatical plural, like Indonesian
+func pluralOther(float64) string {
+	return "other"
+}
+
+// itemName is the display name of a shop item. Items are identified by
+// their English name everywhere else, e.g. in saves and build orders.
+func itemName(name string) string {
+	if _, ok := localeEN.Messages["item."+name]; !ok {
+		return name
+	}
+	return T("item." + name)
+}
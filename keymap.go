@@ -0,0 +1,323 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// KeyAction is something the player can do with a key
+type KeyAction string
+
+const (
+	KeyNone         KeyAction = ""
+	KeyShopUp       KeyAction = "shop_up"
+	KeyShopDown     KeyAction = "shop_down"
+	KeyCategoryPrev KeyAction = "category_prev"
+	KeyCategoryNext KeyAction = "category_next"
+	KeyShopSort     KeyAction = "shop_sort"
+	KeyBuy          KeyAction = "buy"
+	KeyBuyCount     KeyAction = "buy_count"
+	KeyItemsUp      KeyAction = "items_up"
+	KeyItemsDown    KeyAction = "items_down"
+	KeyUpgrade      KeyAction = "upgrade"
+	KeyItemMenu     KeyAction = "item_menu"
+	KeySell         KeyAction = "sell"
+	KeySpawnHunter  KeyAction = "spawn_hunter"
+	KeyAutopilot    KeyAction = "autopilot"
+	KeyAdvisor      KeyAction = "advisor"
+	KeyPause        KeyAction = "pause"
+	KeySpeed        KeyAction = "speed"
+	KeyLogFilter    KeyAction = "log_filter"
+	KeyLogSearch    KeyAction = "log_search"
+	KeyLogExport    KeyAction = "log_export"
+	KeyLogUp        KeyAction = "log_up"
+	KeyLogDown      KeyAction = "log_down"
+	KeyNextTab      KeyAction = "next_tab"
+	KeyLayout       KeyAction = "layout"
+	KeyEffects      KeyAction = "effects"
+	KeyQuit         KeyAction = "quit"
+)
+
+// defaultKeys are the bindings used for actions the keymap file leaves out
+var defaultKeys = map[KeyAction][]string{
+	KeyShopUp:       {"Up"},
+	KeyShopDown:     {"Down"},
+	KeyCategoryPrev: {"Left"},
+	KeyCategoryNext: {"Right"},
+	KeyShopSort:     {"t"},
+	KeyBuy:          {"i"},
+	KeyBuyCount:     {"m"},
+	KeyItemsUp:      {"w"},
+	KeyItemsDown:    {"s"},
+	KeyUpgrade:      {"u"},
+	KeyItemMenu:     {"Enter"},
+	KeySell:         {"r"},
+	KeySpawnHunter:  {"h"},
+	KeyAutopilot:    {"a"},
+	KeyAdvisor:      {"v"},
+	KeyPause:        {"p"},
+	KeySpeed:        {"f"},
+	KeyLogFilter:    {"l"},
+	KeyLogSearch:    {"/"},
+	KeyLogExport:    {"e"},
+	KeyLogUp:        {"PgUp"},
+	KeyLogDown:      {"PgDn"},
+	KeyNextTab:      {"Tab"},
+	KeyLayout:       {"o"},
+	KeyEffects:      {"x"},
+	KeyQuit:         {"q"},
+}
+
+// helpEntries is the order and wording of the help bar. Actions sharing
+// an entry are shown as one key pair, e.g. ←/→:Category.
+var helpEntries = []struct {
+	label   string
+	actions []KeyAction
+}{
+	{"Category", []KeyAction{KeyCategoryPrev, KeyCategoryNext}},
+	{"Select", []KeyAction{KeyShopUp, KeyShopDown}},
+	{"Sort", []KeyAction{KeyShopSort}},
+	{"Buy", []KeyAction{KeyBuy}},
+	{"BuyMode", []KeyAction{KeyBuyCount}},
+	{"ItemNav", []KeyAction{KeyItemsDown, KeyItemsUp}},
+	{"Upgrade", []KeyAction{KeyUpgrade}},
+	{"Sell", []KeyAction{KeySell}},
+	{"Menu", []KeyAction{KeyItemMenu}},
+	{"SpawnHunter", []KeyAction{KeySpawnHunter}},
+	{"Autopilot", []KeyAction{KeyAutopilot}},
+	{"Advisor", []KeyAction{KeyAdvisor}},
+	{"Pause", []KeyAction{KeyPause}},
+	{"Speed", []KeyAction{KeySpeed}},
+	{"Effects", []KeyAction{KeyEffects}},
+	{"LogFilter", []KeyAction{KeyLogFilter}},
+	{"Search", []KeyAction{KeyLogSearch}},
+	{"Export", []KeyAction{KeyLogExport}},
+	{"Scroll", []KeyAction{KeyLogUp, KeyLogDown}},
+	{"Tabs", []KeyAction{KeyNextTab}},
+	{"Layout", []KeyAction{KeyLayout}},
+	{"Quit", []KeyAction{KeyQuit}},
+}
+
+// keyBinding is one physical key: a special key, or a rune when key is
+// tcell.KeyRune. Letters are stored lower case and match either case.
+type keyBinding struct {
+	key tcell.Key
+	r   rune
+}
+
+// Keymap maps keys to actions
+type Keymap struct {
+	keys     map[KeyAction][]keyBinding
+	bindings map[keyBinding]KeyAction
+}
+
+// DefaultKeymapPath is keymap.json next to the config file
+func DefaultKeymapPath(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), "keymap.json")
+}
+
+// DefaultKeymap returns the built-in bindings
+func DefaultKeymap() *Keymap {
+	km, err := NewKeymap(nil)
+	if err != nil {
+		panic(err)
+	}
+	return km
+}
+
+// NewKeymap builds a keymap from action -> key names, falling back to the
+// defaults for actions not listed. It fails on unknown actions or keys
+// and on keys bound to more than one action.
+func NewKeymap(overrides map[string][]string) (*Keymap, error) {
+	names := map[KeyAction][]string{}
+	for action, keys := range defaultKeys {
+		names[action] = keys
+	}
+	for action, keys := range overrides {
+		if _, ok := defaultKeys[KeyAction(action)]; !ok {
+			return nil, fmt.Errorf("unknown key action %q", action)
+		}
+		names[KeyAction(action)] = keys
+	}
+
+	km := &Keymap{keys: map[KeyAction][]keyBinding{}, bindings: map[keyBinding]KeyAction{}}
+	for _, action := range KeyActions() {
+		for _, name := range names[action] {
+			b, err := parseKey(name)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", action, err)
+			}
+			if b == (keyBinding{key: tcell.KeyCtrlC}) {
+				return nil, fmt.Errorf("%s: Ctrl-C is reserved for quitting", action)
+			}
+			if other, taken := km.bindings[b]; taken {
+				if other == action {
+					continue
+				}
+				return nil, fmt.Errorf("key %s is bound to both %s and %s", b, other, action)
+			}
+			km.bindings[b] = action
+			km.keys[action] = append(km.keys[action], b)
+		}
+	}
+	return km, nil
+}
+
+// LoadKeymap reads a JSON object of action -> list of keys from path.
+// A missing file yields the default keymap.
+func LoadKeymap(path string) (*Keymap, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return DefaultKeymap(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	overrides := map[string][]string{}
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	km, err := NewKeymap(overrides)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return km, nil
+}
+
+// KeyActions returns every bindable action in sorted order
+func KeyActions() []KeyAction {
+	actions := []KeyAction{}
+	for action := range defaultKeys {
+		actions = append(actions, action)
+	}
+	sort.Slice(actions, func(i, j int) bool { return actions[i] < actions[j] })
+	return actions
+}
+
+// Lookup returns the action bound to a key event, or KeyNone
+func (km *Keymap) Lookup(event *tcell.EventKey) KeyAction {
+	b := keyBinding{key: event.Key()}
+	if b.key == tcell.KeyRune {
+		b.r = unicode.ToLower(event.Rune())
+	}
+	return km.bindings[b]
+}
+
+// Keys returns the display names of the keys bound to action
+func (km *Keymap) Keys(action KeyAction) []string {
+	names := []string{}
+	for _, b := range km.keys[action] {
+		names = append(names, b.String())
+	}
+	return names
+}
+
+// HelpText renders the help bar for the active bindings. Unbound actions
+// are left out.
+func (km *Keymap) HelpText() string {
+	parts := []string{}
+	for _, entry := range helpEntries {
+		keys := km.boundKeys(entry.actions)
+		if len(keys) == 0 {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("[yellow]%s:[white]%s", tview.Escape(strings.Join(keys, "/")), T("help."+entry.label)))
+	}
+	return "[yellow]" + T("help.keys") + "[white] " + strings.Join(parts, "  ")
+}
+
+// ItemsHint is the key hint above the Your Items panel
+func (km *Keymap) ItemsHint() string {
+	return tview.Escape(T("ui.items_hint", km.hint(KeyItemsDown, KeyItemsUp), km.hint(KeyUpgrade), km.hint(KeySell)))
+}
+
+// ShopHint is the key hint above the shop
+func (km *Keymap) ShopHint() string {
+	return tview.Escape(T("ui.shop_hint", km.hint(KeyCategoryPrev, KeyCategoryNext), km.hint(KeyShopUp, KeyShopDown), km.hint(KeyBuy)))
+}
+
+// hint joins the keys bound to actions for an instruction, e.g. "←/→"
+func (km *Keymap) hint(actions ...KeyAction) string {
+	keys := km.boundKeys(actions)
+	if len(keys) == 0 {
+		return T("tutorial.unbound")
+	}
+	return strings.Join(keys, "/")
+}
+
+// boundKeys lists the first key of each bound action for display
+func (km *Keymap) boundKeys(actions []KeyAction) []string {
+	keys := []string{}
+	for _, action := range actions {
+		if bound := km.keys[action]; len(bound) > 0 {
+			keys = append(keys, bound[0].displayName())
+		}
+	}
+	return keys
+}
+
+// keyNames maps lower-case tcell key names such as "pgup" to keys
+var keyNames = func() map[string]tcell.Key {
+	names := map[string]tcell.Key{}
+	for key, name := range tcell.KeyNames {
+		names[strings.ToLower(name)] = key
+	}
+	return names
+}()
+
+// parseKey accepts a single character or a tcell key name like "PgUp",
+// "F1" or "Ctrl-X", case-insensitively
+func parseKey(name string) (keyBinding, error) {
+	if utf8.RuneCountInString(name) == 1 {
+		r, _ := utf8.DecodeRuneInString(name)
+		return keyBinding{key: tcell.KeyRune, r: unicode.ToLower(r)}, nil
+	}
+	if strings.EqualFold(name, "Space") {
+		return keyBinding{key: tcell.KeyRune, r: ' '}, nil
+	}
+	if key, ok := keyNames[strings.ToLower(name)]; ok {
+		return keyBinding{key: key}, nil
+	}
+	return keyBinding{}, fmt.Errorf("unknown key %q", name)
+}
+
+// String is the key name as written in the keymap file
+func (b keyBinding) String() string {
+	if b.key != tcell.KeyRune {
+		return tcell.KeyNames[b.key]
+	}
+	if b.r == ' ' {
+		return "Space"
+	}
+	return string(b.r)
+}
+
+// displayName is the key as shown in the help bar
+func (b keyBinding) displayName() string {
+	switch b.key {
+	case tcell.KeyRune:
+		if b.r == ' ' {
+			return "Space"
+		}
+		return string(unicode.ToUpper(b.r))
+	case tcell.KeyUp:
+		return glyph("↑", "Up")
+	case tcell.KeyDown:
+		return glyph("↓", "Down")
+	case tcell.KeyLeft:
+		return glyph("←", "Left")
+	case tcell.KeyRight:
+		return glyph("→", "Right")
+	}
+	return tcell.KeyNames[b.key]
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestNewKeymap(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides map[string][]string
+		err       string // part of the error, or "" for none
+	}{
+		{"defaults", nil, ""},
+		{"rebind", map[string][]string{"buy": {"b", "Space"}}, ""},
+		{"swap", map[string][]string{"buy": {"q"}, "quit": {"i"}}, ""},
+		{"same key twice", map[string][]string{"buy": {"b", "B"}}, ""},
+		{"conflict with a default", map[string][]string{"buy": {"q"}}, "bound to both buy and quit"},
+		{"conflict between overrides", map[string][]string{"buy": {"F2"}, "sell": {"f2"}}, "bound to both buy and sell"},
+		{"ctrl-c", map[string][]string{"pause": {"Ctrl-C"}}, "reserved for quitting"},
+		{"unknown action", map[string][]string{"dance": {"d"}}, "unknown key action"},
+		{"unknown key", map[string][]string{"buy": {"Hyper-B"}}, "unknown key"},
+	}
+	for _, tt := range tests {
+		_, err := NewKeymap(tt.overrides)
+		if tt.err == "" && err != nil || tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+			t.Errorf("%s: got %v, want %q", tt.name, err, tt.err)
+		}
+	}
+}
+
+func TestKeymapLookup(t *testing.T) {
+	km, err := NewKeymap(map[string][]string{"buy": {"b", "F2"}, "quit": {}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		event *tcell.EventKey
+		want  KeyAction
+	}{
+		{tcell.NewEventKey(tcell.KeyRune, 'b', tcell.ModNone), KeyBuy},
+		{tcell.NewEventKey(tcell.KeyRune, 'B', tcell.ModShift), KeyBuy},
+		{tcell.NewEventKey(tcell.KeyF2, 0, tcell.ModNone), KeyBuy},
+		{tcell.NewEventKey(tcell.KeyRune, 'i', tcell.ModNone), KeyNone},
+		{tcell.NewEventKey(tcell.KeyRune, 'q', tcell.ModNone), KeyNone},
+		{tcell.NewEventKey(tcell.KeyRune, 'u', tcell.ModNone), KeyUpgrade},
+	}
+	for _, tt := range tests {
+		if got := km.Lookup(tt.event); got != tt.want {
+			t.Errorf("Lookup(%s) = %q, want %q", tt.event.Name(), got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// Layout modes: auto picks wide or tabs from the terminal size
+const (
+	LayoutAuto = "auto"
+	LayoutWide = "wide"
+	LayoutTabs = "tabs"
+)
+
+// LayoutModes lists the layout modes in the order the layout key cycles
+var LayoutModes = []string{LayoutAuto, LayoutWide, LayoutTabs}
+
+// Below this terminal size the auto layout switches to tabs
+const (
+	wideMinWidth  = 100
+	wideMinHeight = 30
+)
+
+// tabNames are the tabs of the single-column layout
+var tabNames = []string{"Shop", "Room", "Map", "Log", "Dreamers"}
+
+// validateLayout rejects unknown layout modes
+func validateLayout(mode string) error {
+	for _, m := range LayoutModes {
+		if m == mode {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown layout %q (want %s)", mode, strings.Join(LayoutModes, ", "))
+}
+
+// responsiveLayout shows the panels side by side on big terminals and
+// one tab at a time on small ones
+type responsiveLayout struct {
+	*tview.Pages
+	mode   string
+	tabbed bool
+	tab    int
+	tabBar *tview.TextView
+	tabs   *tview.Pages
+}
+
+// newResponsiveLayout builds the layout from the wide arrangement and one
+// primitive per entry of tabNames
+func newResponsiveLayout(wide tview.Primitive, tabs []tview.Primitive, mode string) *responsiveLayout {
+	l := &responsiveLayout{
+		Pages:  tview.NewPages(),
+		mode:   mode,
+		tabBar: tview.NewTextView().SetDynamicColors(true).SetRegions(true),
+		tabs:   tview.NewPages(),
+	}
+	for i, tab := range tabs {
+		l.tabs.AddPage(tabNames[i], tab, true, i == 0)
+	}
+	l.tabBar.SetHighlightedFunc(func(added, removed, remaining []string) {
+		if len(added) == 0 {
+			return
+		}
+		l.tabBar.Highlight()
+		if kind, index, ok := parseRegionID(added[0]); ok && kind == regionTab {
+			l.SetTab(index)
+		}
+	})
+
+	single := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(l.tabBar, 1, 0, false).
+		AddItem(l.tabs, 0, 1, false)
+	l.AddPage(LayoutWide, wide, true, true)
+	l.AddPage(LayoutTabs, single, true, false)
+	l.drawTabBar()
+	return l
+}
+
+// Resize picks wide or tabs for a terminal of width x height
+func (l *responsiveLayout) Resize(width, height int) {
+	tabbed := l.mode == LayoutTabs ||
+		l.mode == LayoutAuto && (width < wideMinWidth || height < wideMinHeight)
+	if tabbed == l.tabbed {
+		return
+	}
+	l.tabbed = tabbed
+	if tabbed {
+		l.SwitchToPage(LayoutTabs)
+	} else {
+		l.SwitchToPage(LayoutWide)
+	}
+}
+
+// CycleMode switches to the next layout mode and returns it
+func (l *responsiveLayout) CycleMode() string {
+	for i, m := range LayoutModes {
+		if m == l.mode {
+			l.mode = LayoutModes[(i+1)%len(LayoutModes)]
+			break
+		}
+	}
+	return l.mode
+}
+
+// Tabbed reports whether only one tab is shown
+func (l *responsiveLayout) Tabbed() bool {
+	return l.tabbed
+}
+
+// NextTab shows the tab after the current one, wrapping around
+func (l *responsiveLayout) NextTab() {
+	l.SetTab((l.tab + 1) % len(tabNames))
+}
+
+// SetTab shows tab i
+func (l *responsiveLayout) SetTab(i int) {
+	if i < 0 || i >= len(tabNames) {
+		return
+	}
+	l.tab = i
+	l.tabs.SwitchToPage(tabNames[i])
+	l.drawTabBar()
+}
+
+func (l *responsiveLayout) drawTabBar() {
+	l.tabBar.Clear()
+	for i, name := range tabNames {
+		if i == l.tab {
+			fmt.Fprintf(l.tabBar, `["%s"][black:white] %s [white:-][""] `, regionID(regionTab, i), T("tab."+name))
+		} else {
+			fmt.Fprintf(l.tabBar, `["%s"][gray] %s [white][""] `, regionID(regionTab, i), T("tab."+name))
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+// LogCategory groups log entries so the player can filter them
+type LogCategory int
+
+const (
+	LogSystem LogCategory = iota
+	LogEconomy
+	LogCombat
+)
+
+var logCategoryNames = []string{"system", "economy", "combat"}
+
+func (c LogCategory) String() string {
+	return logCategoryNames[c]
+}
+
+// LogEntry is one line of the Status & Logs panel
+type LogEntry struct {
+	Time     time.Time
+	Category LogCategory
+	Message  string // may contain tview color tags
+}
+
+// logCapacity is how many entries the log keeps before dropping the oldest
+const logCapacity = 500
+
+// LogBuffer keeps the newest log entries and the current view settings
+type LogBuffer struct {
+	mu       sync.Mutex
+	entries  []LogEntry
+	capacity int
+	filter   LogCategory
+	filtered bool   // only show entries of filter
+	search   string // only show entries containing this, case-insensitively
+	version  int    // bumped on every change so views know when to redraw
+}
+
+// NewLogBuffer creates a buffer holding at most capacity entries
+func NewLogBuffer(capacity int) *LogBuffer {
+	return &LogBuffer{capacity: capacity}
+}
+
+var gameLog = NewLogBuffer(logCapacity)
+
+// AddLog appends a message to the game log. A nil logPanel means the game
+// runs headless, in which case nothing is logged.
+func AddLog(logPanel *tview.TextView, category LogCategory, message string) {
+	if logPanel == nil {
+		return
+	}
+	gameLog.Add(LogEntry{Time: time.Now(), Category: category, Message: message})
+}
+
+// Add appends an entry, dropping the oldest one when full
+func (b *LogBuffer) Add(entry LogEntry) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.entries = append(b.entries, entry)
+	if len(b.entries) > b.capacity {
+		b.entries = append(b.entries[:0], b.entries[len(b.entries)-b.capacity:]...)
+	}
+	b.version++
+}
+
+// Clear removes every entry and resets the view settings
+func (b *LogBuffer) Clear() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.entries = nil
+	b.filtered = false
+	b.search = ""
+	b.version++
+}
+
+// CycleFilter steps the category filter: all, system, economy, combat, all
+func (b *LogBuffer) CycleFilter() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch {
+	case !b.filtered:
+		b.filtered, b.filter = true, LogSystem
+	case int(b.filter) < len(logCategoryNames)-1:
+		b.filter++
+	default:
+		b.filtered = false
+	}
+	b.version++
+}
+
+// SetSearch only shows entries containing text; empty shows everything
+func (b *LogBuffer) SetSearch(text string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.search = text
+	b.version++
+}
+
+// Title describes the active filter and search for the panel title
+func (b *LogBuffer) Title() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	title := " " + T("title.log") + " "
+	if b.filtered {
+		title += fmt.Sprintf("[%s] ", T("log.category."+b.filter.String()))
+	}
+	if b.search != "" {
+		title += fmt.Sprintf("/%s ", b.search)
+	}
+	return tview.Escape(title)
+}
+
+// Version changes whenever the rendered log would change
+func (b *LogBuffer) Version() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.version
+}
+
+// Render formats the entries that pass the filter and search
+func (b *LogBuffer) Render() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var sb strings.Builder
+	search := strings.ToLower(b.search)
+	for _, e := range b.entries {
+		if b.filtered && e.Category != b.filter {
+			continue
+		}
+		if search != "" && !strings.Contains(strings.ToLower(stripTags(e.Message)), search) {
+			continue
+		}
+		fmt.Fprintf(&sb, "[yellow]%s[white] %s\n", e.Time.Format("15:04:05"), e.Message)
+	}
+	return sb.String()
+}
+
+// Export writes every retained entry to path as plain text
+func (b *LogBuffer) Export(path string) error {
+	b.mu.Lock()
+	var sb strings.Builder
+	for _, e := range b.entries {
+		fmt.Fprintf(&sb, "%s %-7s %s\n", e.Time.Format("2006-01-02 15:04:05"), e.Category, stripTags(e.Message))
+	}
+	b.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(sb.String()), 0o644)
+}
+
+var colorTag = regexp.MustCompile(`\[[a-zA-Z0-9_,;:\-.#]*\]`)
+
+// stripTags removes tview color tags from s
+func stripTags(s string) string {
+	return colorTag.ReplaceAllString(s, "")
+}
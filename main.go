@@ -1,6 +1,13 @@
 package main
 
 import (
+	"fmt"
+	"math"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"syscall"
 	"time"
 
 	"github.com/gdamore/tcell/v2"
@@ -8,8 +15,46 @@ import (
 )
 
 func main() {
+	os.Exit(RunCLI(os.Args[1:]))
+}
+
+// tuiOptions configure an interactive session
+type tuiOptions struct {
+	slotDir    string     // where saves, replays and stats are kept
+	seed       int64      // seed of a new game
+	resume     *Recording // unfinished game to continue, or nil
+	theme      *Theme
+	keymap     *Keymap
+	tutorial   bool    // play the tutorial instead of a normal game
+	layout     string  // layout mode; changes are saved to configPath
+	effects    bool    // start with combat effects on
+	sellRefund float64 // overrides the refund of restarted games, 0 keeps each difficulty's
+	configPath string
+}
+
+// runTUI plays the game in the terminal until the player quits
+func runTUI(opts tuiOptions) int {
 	app := tview.NewApplication()
-	InitGame()
+	if opts.tutorial {
+		StartTutorial(opts.seed, opts.keymap)
+	} else if opts.resume != nil {
+		ReplayRecording(opts.resume, nil, nil)
+		recording = opts.resume
+	} else {
+		StartRecording(opts.seed)
+	}
+	// Remote players' actions are not part of the recording, so a hosted
+	// game could not be replayed
+	if hostServer != nil {
+		recording = nil
+	}
+
+	// Combat effects follow engine events from here on, so a resumed
+	// game does not replay them
+	if opts.effects {
+		effects = NewEffects()
+	}
+	AddEventListener(func(e Event) { effects.Handle(e) })
 
 	// Top resource panel
 	panelResources := tview.NewTextView().
@@ -29,63 +74,135 @@ func main() {
 		SetChangedFunc(func() {
 			app.Draw()
 		})
-	panelLog.SetBorder(true).SetTitle(" Status & Logs ").SetTitleAlign(tview.AlignLeft)
+	panelLog.SetBorder(true).SetTitle(" " + T("title.log") + " ").SetTitleAlign(tview.AlignLeft)
 
 	panelRoomDefense := tview.NewTextView().
 		SetDynamicColors(true).
 		SetScrollable(true)
-	panelRoomDefense.SetBorder(true).SetTitle(" Dreamers ").SetTitleAlign(tview.AlignLeft)
+	panelRoomDefense.SetBorder(true).SetTitle(" " + T("title.dreamers") + " ").SetTitleAlign(tview.AlignLeft)
 
 	panelRoomItems := tview.NewTextView().
 		SetDynamicColors(true).
 		SetScrollable(true)
-	panelRoomItems.SetBorder(true).SetTitle(" Room Items ").SetTitleAlign(tview.AlignLeft)
+	panelRoomItems.SetBorder(true).SetTitle(" " + T("title.room_items") + " ").SetTitleAlign(tview.AlignLeft)
+
+	panelMap := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true)
+	panelMap.SetBorder(true).SetTitle(" " + T("title.map") + " ").SetTitleAlign(tview.AlignLeft)
 
 	// Right side panels
 	panelYourItems := tview.NewTextView().
 		SetDynamicColors(true).
 		SetScrollable(true)
-	panelYourItems.SetBorder(true).SetTitle(" Your Items ").SetTitleAlign(tview.AlignLeft)
+	panelYourItems.SetBorder(true).SetTitle(" " + T("title.your_items") + " ").SetTitleAlign(tview.AlignLeft)
 
 	panelShop := tview.NewTextView().
 		SetDynamicColors(true).
 		SetScrollable(true)
-	panelShop.SetBorder(true).SetTitle(" Shop ").SetTitleAlign(tview.AlignLeft)
+	panelShop.SetBorder(true).SetTitle(" " + T("title.shop") + " ").SetTitleAlign(tview.AlignLeft)
 
 	// Bottom help panel
 	panelHelp := tview.NewTextView().
 		SetDynamicColors(true).
 		SetScrollable(false).
 		SetTextAlign(tview.AlignCenter).
-		SetText("[yellow]Keys:[white] ←/→:Category  ↑/↓:Select  [yellow]I:[white]Buy  [yellow]S/W:[white]ItemNav  [yellow]U:[white]Upgrade  [yellow]H:[white]SpawnHunter  [yellow]Q:[white]Quit")
+		SetText(opts.keymap.HelpText())
 	panelHelp.SetBorder(true)
 
+	// Panels the tutorial can point at
+	tutorialPanels := map[string]*tview.TextView{
+		PanelShop:      panelShop,
+		PanelYourItems: panelYourItems,
+		PanelRoomItems: panelRoomItems,
+	}
+
 	selectedItem := 0
-	shopCategory := 0 // 0=Coins, 1=Diamonds, 2=Guns
+	shopCategory := 0 // 0=Coins, 1=Diamonds, 2=Guns, 3=Auto-buyers
+	buyCount := 1     // batch size of a purchase: 1, 10 or BuyMax
+	shopSort := ShopSortNone
+	var autopilot Strategy // nil while the player is in control
+	showAdvisor := true
+	paused := false
+	pausedByFocus := false // auto-paused because the terminal lost focus
+	speed := 1             // engine steps per tick: 1x, 2x or 4x
+
+	// The shop cursor stays on its item when the view changes under it,
+	// e.g. when the item stops being affordable; it only moves on input
+	shopView := func() []int {
+		return ShopView(GetAvailableItemsByCategory(shopCategory), shopCategory, buyCount, shopSort)
+	}
+
+	// moveShopSelection moves the shop cursor delta rows in display order,
+	// landing on the first row when the selected item is not shown
+	moveShopSelection := func(delta int) {
+		view := shopView()
+		if len(view) == 0 {
+			return
+		}
+		pos := slices.Index(view, selectedItem)
+		if pos < 0 {
+			selectedItem = view[0]
+			return
+		}
+		selectedItem = view[max(0, min(len(view)-1, pos+delta))]
+	}
 
 	// Function to update all panels
 	updatePanels := func() {
 		UpdateLogPanel(panelLog)
 		UpdateResourcePanel(panelResources)
-		UpdateItemsPanel(panelYourItems)
-		UpdateShopPanel(panelShop, selectedItem, shopCategory)
+		if paused {
+			fmt.Fprintf(panelResources, "  [red]%s[white]", T("ui.paused"))
+		} else {
+			fmt.Fprintf(panelResources, "  [yellow]%s[white]", T("ui.speed", speed, glyph("×", "x")))
+		}
+		if autopilot != nil {
+			fmt.Fprintf(panelResources, "  [green]%s[white]", T("ui.autopilot", autopilot.Name()))
+		}
+		UpdateItemsPanel(panelYourItems, opts.keymap)
+		UpdateShopPanel(panelShop, opts.keymap, selectedItem, shopCategory, buyCount, shopSort, showAdvisor)
 		UpdateRoomDefensePanel(panelRoomDefense)
 		UpdateRoomItemsPanel(panelRoomItems)
+		UpdateMapPanel(panelMap)
+
+		// The tutorial replaces the key help and outlines its panel
+		if tutorial != nil {
+			panelHelp.SetText("[green]" + T("ui.tutorial") + "[white] " + tview.Escape(tutorial.Text()))
+		} else {
+			panelHelp.SetText(opts.keymap.HelpText())
+		}
+		for name, panel := range tutorialPanels {
+			if name == tutorial.Panel() {
+				panel.SetBorderColor(tcell.ColorYellow)
+			} else {
+				panel.SetBorderColor(tview.Styles.BorderColor)
+			}
+		}
 	}
 
 	// Initial panel update (must be before AddLog)
 	updatePanels()
 
 	// Initial log messages
-	AddLog(panelLog, "[green]Welcome to Haunted Room Defense![white]")
-	AddLog(panelLog, "[cyan]Defend your room from Dream Hunters![white]")
-	AddLog(panelLog, "[yellow]Buy beds to generate coins![white]")
+	AddLog(panelLog, LogSystem, "[green]"+T("log.welcome")+"[white]")
+	AddLog(panelLog, LogSystem, "[cyan]"+T("log.welcome_goal")+"[white]")
+	AddLog(panelLog, LogSystem, "[yellow]"+T("log.welcome_tip")+"[white]")
+	tutorial.Update(panelLog)
+	if opts.resume != nil {
+		seconds := math.Round(gameState.elapsed.Seconds())
+		AddLog(panelLog, LogSystem, "[cyan]"+Tn("log.resumed", seconds, FormatDecimal(seconds, 0))+"[white]")
+	}
+	if hostServer != nil {
+		AddLog(panelLog, LogSystem, "[cyan]"+T("log.hosting", hostServer.Addr(), programName(), hostServer.Addr())+"[white]")
+	}
 	updatePanels()
 
-	// Bottom row: Room Defense and Room Items side by side
+	// Bottom row: Room Defense, the map and Room Items side by side
 	bottomRow := tview.NewFlex().
 		SetDirection(tview.FlexColumn).
 		AddItem(panelRoomDefense, 0, 1, false).
+		AddItem(panelMap, 0, 1, false).
 		AddItem(panelRoomItems, 0, 1, false)
 	bottomRow.SetBorderPadding(0, 0, 0, 0)
 
@@ -103,12 +220,19 @@ func main() {
 		AddItem(panelShop, 0, 2, false)
 	rightColumn.SetBorderPadding(0, 0, 0, 0)
 
-	// Main content: left column and right column side by side
-	mainContent := tview.NewFlex().
+	// Wide content: left column and right column side by side
+	wideContent := tview.NewFlex().
 		SetDirection(tview.FlexColumn).
 		AddItem(leftColumn, 0, 2, false).
 		AddItem(rightColumn, 0, 1, true)
-	mainContent.SetBorderPadding(0, 0, 0, 0)
+	wideContent.SetBorderPadding(0, 0, 0, 0)
+
+	// Small terminals get one tab at a time; the Shop tab keeps Your Items
+	shopTab := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(panelYourItems, 0, 1, false).
+		AddItem(panelShop, 0, 2, false)
+	mainContent := newResponsiveLayout(wideContent, []tview.Primitive{shopTab, panelRoomItems, panelMap, panelLog, panelRoomDefense}, opts.layout)
 
 	// Overall layout: resource panel on top, main content in middle, help panel at bottom
 	flex := tview.NewFlex().
@@ -118,14 +242,13 @@ func main() {
 		AddItem(panelHelp, 3, 0, false)
 	flex.SetBorderPadding(0, 0, 0, 0)
 
-	// Game loop ticker
-	ticker := time.NewTicker(1 * time.Second)
-	combatTicker := time.NewTicker(100 * time.Millisecond) // Combat updates 10x per second
+	// Game loop ticker: one engine step per tick at 1x speed
+	ticker := time.NewTicker(StepDuration)
 
 	// Create game over modal (without done func yet)
 	gameOverModal := tview.NewModal().
 		SetText("").
-		AddButtons([]string{"Yes", "No"}).
+		AddButtons(append(gameOverButtons(), T("button.quit"))).
 		SetBackgroundColor(tcell.ColorBlack).
 		SetButtonBackgroundColor(tcell.ColorBlack).
 		SetButtonTextColor(tcell.ColorWhite).
@@ -136,11 +259,183 @@ func main() {
 		AddPage("main", flex, true, true).
 		AddPage("gameOver", gameOverModal, true, false)
 
+	// Log search box, shown at the bottom while typing a search
+	searchField := tview.NewInputField().
+		SetLabel(T("ui.search")).
+		SetFieldWidth(0)
+	searchField.SetBorder(true)
+	searchField.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			searchField.SetText("")
+		}
+		gameLog.SetSearch(searchField.GetText())
+		pages.HidePage("search")
+		app.SetFocus(pages)
+		updatePanels()
+	})
+	searchBox := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(nil, 0, 1, false).
+		AddItem(searchField, 3, 0, true)
+	pages.AddPage("search", searchBox, true, false)
+
+	// Mouse: click a tab or shop row to select it, double-click a row to
+	// buy. tview calls any two quick clicks a double-click, so a buy also
+	// needs the first click to have been a left click on the same row.
+	doubleClick := false
+	var clickedRegion, prevClickedRegion string // regions of the last two left clicks in the shop
+	panelShop.SetRegions(true).
+		SetHighlightedFunc(func(added, removed, remaining []string) {
+			if len(added) == 0 {
+				return
+			}
+			panelShop.Highlight() // selection is drawn by UpdateShopPanel
+			clickedRegion = added[0]
+			kind, index, ok := parseRegionID(added[0])
+			if !ok {
+				return
+			}
+			switch kind {
+			case regionTab:
+				shopCategory, selectedItem = index, -1
+				moveShopSelection(0)
+			case regionSort:
+				shopSort = shopSorts[index]
+			case regionBuyCount:
+				buyCount = buyCounts[index]
+			case regionShop:
+				selectedItem = index
+				if doubleClick && prevClickedRegion == added[0] {
+					ApplyAction(Action{Kind: ActionBuy, Category: shopCategory, Index: selectedItem, Count: buyCount}, panelLog)
+				}
+			}
+			updatePanels()
+		})
+	panelShop.SetMouseCapture(func(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
+		switch action {
+		case tview.MouseLeftClick, tview.MouseLeftDoubleClick:
+			doubleClick = action == tview.MouseLeftDoubleClick
+			prevClickedRegion, clickedRegion = clickedRegion, ""
+			return tview.MouseLeftClick, event
+		case tview.MouseRightClick, tview.MouseRightDoubleClick, tview.MouseMiddleClick, tview.MouseMiddleDoubleClick:
+			clickedRegion = ""
+		}
+		return action, event
+	})
+
+	// Right-clicking a Your Items row opens a small menu next to the mouse
+	itemMenu := tview.NewList().ShowSecondaryText(false)
+	itemMenu.SetBorder(true).SetTitle(" " + T("title.item_menu") + " ").SetTitleAlign(tview.AlignLeft)
+	pages.AddPage("itemMenu", itemMenu, false, false)
+	hideItemMenu := func() {
+		pages.HidePage("itemMenu")
+		app.SetFocus(pages)
+	}
+	itemMenu.SetDoneFunc(hideItemMenu)
+	showItemMenu := func(index, x, y int) {
+		itemMenu.Clear()
+		width := 0
+		add := func(label string, shortcut rune, action Action) {
+			itemMenu.AddItem(label, "", shortcut, func() {
+				hideItemMenu()
+				ApplyAction(action, panelLog)
+				updatePanels()
+			})
+			width = max(width, tview.TaggedStringWidth(label))
+		}
+		// Auto-buyers have their rules here instead of an upgrade
+		if buyer, ok := autoBuyerRow(index); ok {
+			b := GetGameState().autoBuyers[buyer]
+			toggle, idle := T("menu.turn_on"), T("menu.idle_only_on")
+			if b.enabled {
+				toggle = T("menu.turn_off")
+			}
+			if b.idleOnly {
+				idle = T("menu.idle_only_off")
+			}
+			add(toggle, 'u', Action{Kind: ActionAutoBuyerToggle, Index: buyer})
+			add(T("menu.reserve", b.reserve), 'r', Action{Kind: ActionAutoBuyerReserve, Index: buyer})
+			add(idle, 'i', Action{Kind: ActionAutoBuyerIdle, Index: buyer})
+		} else {
+			add(T("menu.upgrade", GetGameState().itemsPanelItems[index]), 'u', Action{Kind: ActionUpgrade, Index: index})
+			if coins, diamonds, ok := SellValue(index); ok {
+				add(T("menu.sell", costString(coins, diamonds)), 'r', Action{Kind: ActionSell, Index: index})
+			}
+		}
+		itemMenu.AddItem(T("menu.cancel"), "", 0, hideItemMenu)
+		itemMenu.SetRect(x, y, width+6, itemMenu.GetItemCount()+2)
+		pages.ShowPage("itemMenu")
+		app.SetFocus(itemMenu)
+	}
+
+	// Mouse: click a Your Items row to select it, right-click for its menu
+	menuOnSelect := false
+	var menuX, menuY int
+	panelYourItems.SetRegions(true).
+		SetHighlightedFunc(func(added, removed, remaining []string) {
+			if len(added) == 0 {
+				return
+			}
+			panelYourItems.Highlight()
+			kind, index, ok := parseRegionID(added[0])
+			if !ok || kind != regionOwned {
+				return
+			}
+			SelectItem(index)
+			if menuOnSelect {
+				showItemMenu(index, menuX, menuY)
+			}
+			updatePanels()
+		})
+	panelYourItems.SetMouseCapture(func(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
+		menuOnSelect = action == tview.MouseRightClick
+		if menuOnSelect {
+			menuX, menuY = event.Position()
+			return tview.MouseLeftClick, event
+		}
+		return action, event
+	})
+
+	// Mouse wheel scrolls the log like PgUp/PgDn
+	panelLog.SetMouseCapture(func(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
+		switch action {
+		case tview.MouseScrollUp:
+			ScrollLogPanel(panelLog, -3)
+			return action, nil
+		case tview.MouseScrollDown:
+			ScrollLogPanel(panelLog, 3)
+			return action, nil
+		}
+		return action, event
+	})
+
+	// A click outside the item menu closes it, and one outside the shop
+	// keeps the next shop click from counting as a double-click
+	app.SetMouseCapture(func(event *tcell.EventMouse, action tview.MouseAction) (*tcell.EventMouse, tview.MouseAction) {
+		if x, y := event.Position(); action == tview.MouseLeftDown && !panelShop.InRect(x, y) {
+			clickedRegion = ""
+		}
+		if name, _ := pages.GetFrontPage(); name == "itemMenu" && action == tview.MouseLeftDown {
+			if x, y := event.Position(); !itemMenu.InRect(x, y) {
+				hideItemMenu()
+			}
+		}
+		return event, action
+	})
+
 	// Set modal done function (now that pages is declared)
 	gameOverModal.SetDoneFunc(func(buttonIndex int, buttonLabel string) {
-		if buttonLabel == "Yes" {
-			// Restart game
-			InitGame()
+		if buttonIndex >= 0 && buttonIndex <= len(DifficultyNames) {
+			// Restart game on the chosen difficulty, or the last button
+			// before Quit on the same settings, custom ones included
+			if buttonIndex < len(DifficultyNames) {
+				SetDifficulty(difficultyPresets[DifficultyNames[buttonIndex]].WithSellRefund(opts.sellRefund))
+			}
+			tutorial = nil
+			StartRecording(time.Now().UnixNano())
+			if hostServer != nil {
+				recording = nil
+			}
 			selectedItem = 0
 			shopCategory = 0
 			pages.HidePage("gameOver")
@@ -148,42 +443,66 @@ func main() {
 		} else {
 			// Quit
 			ticker.Stop()
-			combatTicker.Stop()
 			app.Stop()
 		}
 	})
 
+	// A hangup (the terminal or SSH connection going away) or a request
+	// to terminate quits like the quit key, so the game is still saved
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP, syscall.SIGTERM)
 	go func() {
-		hunterSpawnCounter := 0
-		for range ticker.C {
-			UpdateGame()
-
-			// Check for game over
-			gs := GetGameState()
-			if gs.gameOver {
-				if gs.gameWon {
-					gameOverModal.SetText("🎉 VICTORY! 🎉\nYou defeated the Dream Hunter!\n\nPlay again?")
-				} else {
-					gameOverModal.SetText("💀 GAME OVER 💀\nYour door was destroyed!\n\nPlay again?")
-				}
-				pages.ShowPage("gameOver")
-			}
+		<-signals
+		ticker.Stop()
+		app.Stop()
+	}()
+
+	// Every tick runs on the UI goroutine, like the input handlers, so the
+	// game and the autopilot, pause and speed settings only ever change
+	// on one goroutine
+	tick := func() {
+		// Remote players join and buy between engine steps, even while
+		// paused, and spectators get a fresh snapshot
+		hostServer.Sync(panelLog)
+		spectator.Publish()
+
+		// Paused: economy, combat and spawn timers all stand still, but
+		// the panels are still redrawn so running effects play out
+		if paused {
+			updatePanels()
+			return
+		}
 
-			// Spawn hunter every 10 seconds
-			hunterSpawnCounter++
-			if hunterSpawnCounter >= 10 {
-				SpawnHunter(panelLog)
-				hunterSpawnCounter = 0
+		for i := 0; i < speed; i++ {
+			StepGame(panelLog)
+			if autopilot != nil {
+				RunStrategyStep(autopilot, panelLog)
 			}
+			tutorial.Update(panelLog)
+		}
 
-			updatePanels()
+		// Check for game over
+		gs := GetGameState()
+		// A won fight shows the modal once the hunter's death animation ends
+		if gs.gameOver && !effects.Busy() {
+			if tutorial != nil && tutorial.Finished() {
+				gameOverModal.SetText(banner("🎉", T("modal.tutorial_complete")) + "\n" + T("modal.tutorial_complete_text") + "\n\n" + T("modal.real_game"))
+			} else if gs.gameWon {
+				gameOverModal.SetText(banner("🎉", T("modal.victory")) + "\n" + T("modal.victory_text") + "\n\n" + T("modal.play_again"))
+			} else {
+				gameOverModal.SetText(banner("💀", T("modal.game_over")) + "\n" + T("modal.game_over_text") + "\n\n" + T("modal.play_again"))
+			}
+			if err := FinishGame(opts.slotDir); err != nil {
+				AddLog(panelLog, LogSystem, "[red]"+T("log.record_failed", err)+"[white]")
+			}
+			pages.ShowPage("gameOver")
 		}
-	}()
 
+		updatePanels()
+	}
 	go func() {
-		for range combatTicker.C {
-			UpdateCombat(panelLog)
-			updatePanels()
+		for range ticker.C {
+			app.QueueUpdateDraw(tick)
 		}
 	}()
 
@@ -195,78 +514,193 @@ func main() {
 			return event
 		}
 
-		items := GetAvailableItemsByCategory(shopCategory)
+		// While typing a log search or using the item menu, keys belong to it
+		if name, _ := pages.GetFrontPage(); name == "search" || name == "itemMenu" {
+			return event
+		}
 
-		switch event.Key() {
-		case tcell.KeyCtrlC:
-			// Exit application
+		if event.Key() == tcell.KeyCtrlC {
+			// Ctrl-C always quits, whatever the keymap says
 			ticker.Stop()
-			combatTicker.Stop()
 			app.Stop()
 			return nil
-		case tcell.KeyUp:
+		}
+
+		switch opts.keymap.Lookup(event) {
+		case KeyShopUp:
 			// Move selection up
-			if selectedItem > 0 {
-				selectedItem--
-				updatePanels()
-			}
+			moveShopSelection(-1)
+			updatePanels()
 			return nil
-		case tcell.KeyDown:
+		case KeyShopDown:
 			// Move selection down
-			if selectedItem < len(items)-1 {
-				selectedItem++
-				updatePanels()
-			}
+			moveShopSelection(1)
+			updatePanels()
 			return nil
-		case tcell.KeyLeft:
+		case KeyCategoryPrev:
 			// Previous category
 			if shopCategory > 0 {
 				shopCategory--
-				selectedItem = 0
+				selectedItem = -1
+				moveShopSelection(0) // first shown row
 				updatePanels()
 			}
 			return nil
-		case tcell.KeyRight:
+		case KeyCategoryNext:
 			// Next category
-			if shopCategory < 2 {
+			if shopCategory < shopCategories-1 {
 				shopCategory++
-				selectedItem = 0
+				selectedItem = -1
+				moveShopSelection(0)
 				updatePanels()
 			}
 			return nil
-		}
-
-		// Handle character keys
-		switch event.Rune() {
-		case 'i', 'I':
-			// Buy selected item
-			BuyItemByCategory(selectedItem, shopCategory, panelLog)
+		case KeyShopSort:
+			// Cycle the shop order: default, cost, DPS per coin, affordable only
+			shopSort = shopSorts[(slices.Index(shopSorts, shopSort)+1)%len(shopSorts)]
 			updatePanels()
 			return nil
-		case 's', 'S':
+		case KeyLogUp:
+			// Scroll the log back
+			ScrollLogPanel(panelLog, -10)
+			return nil
+		case KeyLogDown:
+			// Scroll the log forward; at the bottom it follows new entries again
+			ScrollLogPanel(panelLog, 10)
+			return nil
+		case KeyBuy:
+			// Buy selected item, unless the view hides it
+			if slices.Contains(shopView(), selectedItem) {
+				ApplyAction(Action{Kind: ActionBuy, Category: shopCategory, Index: selectedItem, Count: buyCount}, panelLog)
+			}
+			updatePanels()
+			return nil
+		case KeyBuyCount:
+			// Cycle the batch size: ×1, ×10, max
+			for i, count := range buyCounts {
+				if count == buyCount {
+					buyCount = buyCounts[(i+1)%len(buyCounts)]
+					break
+				}
+			}
+			updatePanels()
+			return nil
+		case KeyItemsDown:
 			// Move down in Your Items panel
 			MoveItemSelection(1)
 			updatePanels()
 			return nil
-		case 'w', 'W':
+		case KeyItemsUp:
 			// Move up in Your Items panel
 			MoveItemSelection(-1)
 			updatePanels()
 			return nil
-		case 'u', 'U':
-			// Upgrade selected item in Your Items panel
-			UpgradeSelectedItem(panelLog)
+		case KeyUpgrade:
+			// Upgrade selected item in Your Items panel; auto-buyers toggle
+			if buyer, ok := autoBuyerRow(gs.itemsPanelSelected); ok {
+				ApplyAction(Action{Kind: ActionAutoBuyerToggle, Index: buyer}, panelLog)
+			} else {
+				ApplyAction(Action{Kind: ActionUpgrade, Index: gs.itemsPanelSelected}, panelLog)
+			}
+			updatePanels()
+			return nil
+		case KeySell:
+			// Sell selected item in Your Items panel
+			ApplyAction(Action{Kind: ActionSell, Index: gs.itemsPanelSelected}, panelLog)
 			updatePanels()
 			return nil
-		case 'h', 'H':
+		case KeyItemMenu:
+			// Open the menu of the selected Your Items row next to it
+			if gs.itemsPanelSelected < len(gs.itemsPanelItems) {
+				x, y, _, _ := panelYourItems.GetInnerRect()
+				row, _ := panelYourItems.GetScrollOffset()
+				showItemMenu(gs.itemsPanelSelected, x+2, y+2+gs.itemsPanelSelected-row)
+			}
+			return nil
+		case KeySpawnHunter:
 			// Spawn hunter manually for testing
-			SpawnHunter(panelLog)
+			ApplyAction(Action{Kind: ActionSpawn}, panelLog)
+			updatePanels()
+			return nil
+		case KeyAutopilot:
+			// Cycle autopilot: off -> each built-in strategy -> off
+			autopilot = nextAutopilot(autopilot)
+			if autopilot != nil {
+				AddLog(panelLog, LogSystem, "[green]"+T("ui.autopilot", autopilot.Name())+"[white]")
+			} else {
+				AddLog(panelLog, LogSystem, "[yellow]"+T("log.autopilot_off")+"[white]")
+			}
+			updatePanels()
+			return nil
+		case KeyAdvisor:
+			// Toggle the shop advisor
+			showAdvisor = !showAdvisor
+			updatePanels()
+			return nil
+		case KeyPause:
+			// Pause or resume
+			paused = !paused
+			pausedByFocus = false
+			updatePanels()
+			return nil
+		case KeySpeed:
+			// Cycle game speed 1x -> 2x -> 4x
+			speed *= 2
+			if speed > 4 {
+				speed = 1
+			}
+			updatePanels()
+			return nil
+		case KeyLogFilter:
+			// Cycle the log category filter
+			gameLog.CycleFilter()
+			updatePanels()
+			return nil
+		case KeyLogSearch:
+			// Search the log
+			searchField.SetText("")
+			pages.ShowPage("search")
+			app.SetFocus(searchField)
+			return nil
+		case KeyLogExport:
+			// Export the log to a file in the save slot
+			path := filepath.Join(opts.slotDir, "logs", fmt.Sprintf("log-%s.txt", time.Now().Format("20060102-150405")))
+			if err := gameLog.Export(path); err != nil {
+				AddLog(panelLog, LogSystem, "[red]"+T("log.export_failed", err)+"[white]")
+			} else {
+				AddLog(panelLog, LogSystem, "[green]"+T("log.exported", tview.Escape(path))+"[white]")
+			}
+			updatePanels()
+			return nil
+		case KeyNextTab:
+			// Show the next tab of the single-column layout
+			if mainContent.Tabbed() {
+				mainContent.NextTab()
+			}
+			return nil
+		case KeyLayout:
+			// Cycle auto/wide/tabs and remember the choice
+			mode := mainContent.CycleMode()
+			if err := saveLayout(opts.configPath, mode); err != nil {
+				AddLog(panelLog, LogSystem, "[red]"+T("log.layout_failed", err)+"[white]")
+			} else {
+				AddLog(panelLog, LogSystem, "[green]"+T("log.layout", mode)+"[white]")
+			}
+			updatePanels()
+			return nil
+		case KeyEffects:
+			if effects != nil {
+				effects = nil
+				AddLog(panelLog, LogSystem, "[yellow]"+T("log.effects_off")+"[white]")
+			} else {
+				effects = NewEffects()
+				AddLog(panelLog, LogSystem, "[green]"+T("log.effects_on")+"[white]")
+			}
 			updatePanels()
 			return nil
-		case 'q', 'Q':
+		case KeyQuit:
 			// Quit
 			ticker.Stop()
-			combatTicker.Stop()
 			app.Stop()
 			return nil
 		}
@@ -274,8 +708,134 @@ func main() {
 		return event
 	})
 
+	// Auto-pause while the terminal is unfocused, if it reports focus. Focus
+	// events arrive on tview's polling goroutine, so the pause is applied on
+	// the UI goroutine along with the ticks.
+	screen, err := tcell.NewScreen()
+	if err != nil {
+		panic(err)
+	}
+	palette, _ := opts.theme.palette() // checked when the theme was loaded
+	app.SetScreen(&gameScreen{Screen: screen, monochrome: opts.theme.Monochrome, palette: palette, onFocus: func(focused bool) {
+		app.QueueUpdateDraw(func() {
+			if !focused && !paused {
+				paused, pausedByFocus = true, true
+			} else if focused && pausedByFocus {
+				paused, pausedByFocus = false, false
+			}
+			updatePanels()
+		})
+	}})
+
+	// Pick the layout for the terminal size before every draw
+	app.SetBeforeDrawFunc(func(screen tcell.Screen) bool {
+		mainContent.Resize(screen.Size())
+		return false
+	})
+
 	// Run the application
 	if err := app.SetRoot(pages, true).EnableMouse(true).Run(); err != nil {
-		panic(err)
+		fmt.Fprintln(os.Stderr, err)
+		return exitError
+	}
+
+	// Keep an unfinished game for next time
+	if err := WriteSave(opts.slotDir); err != nil {
+		fmt.Fprintf(os.Stderr, "could not save the game: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// gameOverButtons are the restart buttons of the game over modal: one per
+// difficulty in the order of DifficultyNames, then one for the same settings
+func gameOverButtons() []string {
+	buttons := []string{}
+	for _, name := range DifficultyNames {
+		buttons = append(buttons, difficultyName(name))
+	}
+	return append(buttons, T("button.same_settings"))
+}
+
+// banner decorates a modal headline, e.g. "🎉 VICTORY! 🎉"
+func banner(emoji, text string) string {
+	return glyph(emoji+" "+text+" "+emoji, "*** "+text+" ***")
+}
+
+// saveLayout stores the layout mode in the config file
+func saveLayout(configPath, mode string) error {
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		return err
+	}
+	cfg.Layout = mode
+	return cfg.Save(configPath)
+}
+
+// nextAutopilot returns the strategy after current in name order,
+// or nil after the last one
+func nextAutopilot(current Strategy) Strategy {
+	names := StrategyNames()
+	if current == nil {
+		s, _ := GetStrategy(names[0])
+		return s
+	}
+	for i, name := range names {
+		if name == current.Name() && i+1 < len(names) {
+			s, _ := GetStrategy(names[i+1])
+			return s
+		}
+	}
+	return nil
+}
+
+// gameScreen reports terminal focus changes, which tview itself ignores,
+// and applies the color theme to every cell
+type gameScreen struct {
+	tcell.Screen
+	monochrome bool
+	palette    map[tcell.Color]tcell.Color // theme color replacements
+	onFocus    func(focused bool)
+}
+
+func (s *gameScreen) Init() error {
+	if err := s.Screen.Init(); err != nil {
+		return err
+	}
+	s.EnableFocus()
+	return nil
+}
+
+// SetContent draws a cell in the theme's colors. In monochrome mode
+// colors are dropped and highlighted cells (a background color) are shown
+// in reverse video.
+func (s *gameScreen) SetContent(x, y int, primary rune, combining []rune, style tcell.Style) {
+	if s.monochrome {
+		_, bg, attrs := style.Decompose()
+		mono := tcell.StyleDefault.Attributes(attrs)
+		if bg != tcell.ColorDefault && bg != tcell.ColorBlack {
+			mono = mono.Reverse(true)
+		}
+		style = mono
+	} else if len(s.palette) > 0 {
+		fg, bg, _ := style.Decompose()
+		if c, ok := s.palette[fg]; ok {
+			style = style.Foreground(c)
+		}
+		if c, ok := s.palette[bg]; ok {
+			style = style.Background(c)
+		}
+	}
+	s.Screen.SetContent(x, y, primary, combining, style)
+}
+
+func (s *gameScreen) PollEvent() tcell.Event {
+	for {
+		ev := s.Screen.PollEvent()
+		if focus, ok := ev.(*tcell.EventFocus); ok {
+			s.onFocus(focus.Focused)
+			continue
+		}
+		return ev
 	}
 }
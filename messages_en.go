@@ -0,0 +1,220 @@
+package main
+
+// localeEN is the English catalog. Every key must exist here: other
+// languages fall back to it.
+var localeEN = Locale{
+	Name:     "English",
+	Plural:   pluralOneOther,
+	Decimal:  ".",
+	Suffixes: []string{"", "K", "M", "B", "T"},
+	Messages: map[string]string{
+		// Shop items
+		"item.Bed":         "Bed",
+		"item.Door":        "Door",
+		"item.Playbox":     "Playbox",
+		"item.Trap":        "Trap",
+		"item.Guard":       "Guard",
+		"item.Pistol":      "Pistol",
+		"item.Rifle":       "Rifle",
+		"item.Shotgun":     "Shotgun",
+		"item.Machine Gun": "Machine Gun",
+		"item.Sniper":      "Sniper",
+
+		"item.Auto Bed":     "Auto Bed",
+		"item.Auto Door":    "Auto Door",
+		"item.Auto Playbox": "Auto Playbox",
+		"item.Auto Defense": "Auto Defense",
+
+		"desc.autobuyer":      "Buys a %s every second on its own",
+		"desc.coins_per_s":    "+%s coins/s",
+		"desc.diamonds_per_s": "+%s diamonds/s",
+		"desc.door_hp":        "+50 HP",
+		"desc.defense":        "+%d defense",
+		"desc.gun":            "%d dmg, %s atk/s",
+
+		"category.0": "COINS",
+		"category.1": "DIAMONDS",
+		"category.2": "GUNS",
+		"category.3": "AUTO",
+
+		"difficulty.easy":      "Easy",
+		"difficulty.normal":    "Normal",
+		"difficulty.nightmare": "Nightmare",
+		"difficulty.custom":    "Custom",
+
+		// Log messages, panels and dialogs
+		"log.hunter_defeated":          "Dream Hunter defeated! YOU WIN!",
+		"log.hunter_attacks":           "Hunter attacks door! -%d HP",
+		"log.door_broken":              "GAME OVER! Your door is broken!",
+		"log.hunter_spawned":           "Dream Hunter Level %d spawned!",
+		"log.invalid_item":             "Invalid item!",
+		"log.not_enough_resources":     "Not enough resources!",
+		"log.not_enough_coins":         "Not enough coins!",
+		"log.bed_upgraded":             "Bed upgraded to level %d!",
+		"log.bed_upgraded_income":      "Bed upgraded to level %d! (+%s coins/s)",
+		"log.door_upgraded":            "Door upgraded to level %d! (HP: %s)",
+		"log.playbox_upgraded":         "Playbox upgraded to level %d!",
+		"log.playbox_upgraded_income":  "Playbox upgraded to level %d! (+%s diamonds/s)",
+		"log.trap_installed":           "Trap installed! Defense +5",
+		"log.guard_hired":              "Guard hired! Defense +10",
+		"log.gun_purchased":            "%s purchased! Damage: %d, Speed: %s/s",
+		"log.bought_batch":             "Bought %s %s for %s",
+		"log.autobuyer_unlocked":       "%s unlocked!",
+		"log.autobuyer":                "%s:",
+		"log.sell_in_combat":           "Can't sell while a hunter is attacking!",
+		"log.cannot_sell":              "This can't be sold",
+		"log.sold":                     "Sold %s for %s",
+		"log.hosting":                  "Hosting on %s. Players join with: %s join %s",
+		"log.player_joined":            "%s joined as %s",
+		"log.player_left":              "%s left; %s is back to dreaming alone",
+		"log.remote_bought":            "%s bought a %s",
+		"log.hunter_attacks_player":    "Hunter attacks %s's door! -%d HP",
+		"log.player_eliminated":        "%s's door is broken! They are out of the game.",
+		"log.max_level":                "%s is at max level!",
+		"log.welcome":                  "Welcome to Haunted Room Defense!",
+		"log.welcome_goal":             "Defend your room from Dream Hunters!",
+		"log.welcome_tip":              "Buy beds to generate coins!",
+		"log.resumed.one":              "Resumed saved game at %s second",
+		"log.resumed.other":            "Resumed saved game at %s seconds",
+		"log.record_failed":            "Could not record the game: %v",
+		"log.autopilot_off":            "Autopilot off",
+		"log.export_failed":            "Could not export the log: %v",
+		"log.exported":                 "Log exported to %s",
+		"log.effects_on":               "Combat effects on",
+		"log.effects_off":              "Combat effects off",
+		"log.layout_failed":            "Could not save the layout: %v",
+		"log.layout":                   "Layout: %s",
+		"log.category.system":          "system",
+		"log.category.economy":         "economy",
+		"log.category.combat":          "combat",
+		"ui.autopilot":                 "Autopilot: %s",
+		"ui.paused":                    "PAUSED",
+		"ui.speed":                     "Speed: %d%s",
+		"ui.game_over":                 "GAME OVER",
+		"ui.items_hint":                "(%s: move, %s: upgrade, %s: sell)",
+		"ui.shop_hint":                 "(%s: category, %s: item, %s: buy)",
+		"ui.buy":                       "Buy:",
+		"ui.buy_max":                   "max",
+		"ui.sort":                      "Sort:",
+		"sort.default":                 "default",
+		"sort.cost":                    "cost",
+		"sort.dps":                     "DPS/coin",
+		"sort.affordable":              "affordable",
+		"ui.eta.one":                   "affordable in %s second",
+		"ui.eta.other":                 "affordable in %s seconds",
+		"ui.eta_never":                 "no income to afford it",
+		"ui.nothing_affordable":        "Nothing affordable right now",
+		"ui.advisor":                   "Advisor:",
+		"ui.advisor_save":              "save up",
+		"ui.cost_coins":                "%sc",
+		"ui.cost_diamonds":             "%sd",
+		"ui.cost_both":                 "%sc+%sd",
+		"ui.dreamers":                  "DREAMERS",
+		"ui.you":                       "You",
+		"ui.door_level":                "Door Lv%d",
+		"ui.room_items":                "ROOM ITEMS",
+		"ui.door":                      "Door:",
+		"ui.no_items":                  "No items",
+		"ui.hunter_level":              "HUNTER LEVEL %d",
+		"ui.hunter_attack.one":         "Attack: %d every %s second",
+		"ui.hunter_attack.other":       "Attack: %d every %s seconds",
+		"ui.hp":                        "HP:",
+		"ui.coins":                     "Coins: %s (+%s/s)",
+		"ui.diamonds":                  "Diamonds: %s (+%s/s)",
+		"ui.defense":                   "Defense: %d",
+		"ui.tutorial":                  "Tutorial:",
+		"ui.map_legend":                "%s intact %s damaged %s broken %s hunter %s gun",
+		"ui.sell_value":                "sells for %s (%s%% refund)",
+		"ui.search":                    "Search log: ",
+		"client.joining":               "Joining the game at %s...",
+		"client.playing":               "Playing %s as %s at %s",
+		"client.host":                  "Host",
+		"client.hunter":                "HUNTER Lv%d at %s",
+		"client.no_hunter":             "No hunter yet",
+		"client.guns":                  "Guns: %d",
+		"client.shop":                  "SHOP",
+		"client.eliminated":            "Your door is broken. You are out until the next game.",
+		"client.won":                   "The hunter is defeated! Waiting for the host to start a new game.",
+		"client.lost":                  "The host's door is broken! Waiting for the host to start a new game.",
+		"client.disconnected":          "Disconnected from the host",
+		"owned.door":                   "Door Lv%d (HP:%s)",
+		"owned.bed":                    "Bed Lv%d (+%s/s)",
+		"owned.playbox":                "Playbox Lv%d (+%s/s)",
+		"owned.defense":                "Defense: %s",
+		"owned.autobuyer":              "%s: %s, reserve %d%%",
+		"autobuyer.on":                 "on",
+		"autobuyer.off":                "off",
+		"autobuyer.idle_only":          "only between hunters",
+		"owned.gun":                    "%s (D:%d S:%s)",
+		"advice.gun":                   "+%s DPS, kills hunter in %ss (door holds %ss)",
+		"advice.door":                  "+%ss before the door breaks (holds %ss, kill in %ss)",
+		"advice.producer":              "+%s %s, pays back in %ss",
+		"unit.coins_per_s":             "coins/s",
+		"unit.diamonds_per_s":          "diamonds/s",
+		"title.log":                    "Status & Logs",
+		"title.dreamers":               "Dreamers",
+		"title.room_items":             "Room Items",
+		"title.your_items":             "Your Items",
+		"title.map":                    "Dorm Map",
+		"title.shop":                   "Shop",
+		"title.item_menu":              "Item",
+		"title.client":                 "Haunted Room Defense",
+		"menu.upgrade":                 "Upgrade %s",
+		"menu.sell":                    "Sell for %s",
+		"menu.turn_on":                 "Turn on",
+		"menu.turn_off":                "Turn off",
+		"menu.reserve":                 "Reserve %d%% (change)",
+		"menu.idle_only_on":            "Only between hunters",
+		"menu.idle_only_off":           "Also during hunts",
+		"menu.cancel":                  "Cancel",
+		"modal.victory":                "VICTORY!",
+		"modal.victory_text":           "You defeated the Dream Hunter!",
+		"modal.game_over":              "GAME OVER",
+		"modal.game_over_text":         "Your door was destroyed!",
+		"modal.play_again":             "Play again? Pick a difficulty or keep these settings:",
+		"modal.tutorial_complete":      "TUTORIAL COMPLETE",
+		"modal.tutorial_complete_text": "You defeated your first Dream Hunter!",
+		"modal.real_game":              "Ready for a real game? Pick a difficulty:",
+		"button.same_settings":         "Same settings",
+		"button.quit":                  "Quit",
+		"tab.Shop":                     "Shop",
+		"tab.Room":                     "Room",
+		"tab.Map":                      "Map",
+		"tab.Log":                      "Log",
+		"tab.Dreamers":                 "Dreamers",
+
+		// Help bar
+		"help.keys":        "Keys:",
+		"help.Category":    "Category",
+		"help.Select":      "Select",
+		"help.Sell":        "Sell",
+		"help.Menu":        "Menu",
+		"help.Sort":        "Sort",
+		"help.Buy":         "Buy",
+		"help.BuyMode":     "BuyMode",
+		"help.ItemNav":     "ItemNav",
+		"help.Upgrade":     "Upgrade",
+		"help.SpawnHunter": "SpawnHunter",
+		"help.Autopilot":   "Autopilot",
+		"help.Advisor":     "Advisor",
+		"help.Pause":       "Pause",
+		"help.Speed":       "Speed",
+		"help.Effects":     "Effects",
+		"help.LogFilter":   "LogFilter",
+		"help.Search":      "Search",
+		"help.Export":      "Export",
+		"help.Scroll":      "Scroll",
+		"help.Tabs":        "Tabs",
+		"help.Layout":      "Layout",
+		"help.Quit":        "Quit",
+
+		// Tutorial
+		"tutorial.step":     "Step %d/%d: %s",
+		"tutorial.bed":      "Buy a Bed: it is in the Coins category of the Shop (%s). Select it with %s and press %s. Beds earn coins every second.",
+		"tutorial.door":     "Upgrade your Door: select it in Your Items with %s and press %s. A stronger door holds out longer.",
+		"tutorial.pistol":   "Buy a Pistol from the Guns category (%s). Guns shoot hunters on their own.",
+		"tutorial.fight":    "A weak Dream Hunter is coming! Watch its HP in Room Items and keep your door standing until your guns defeat it.",
+		"tutorial.complete": "Tutorial complete! Pick a difficulty to start a real game.",
+		"tutorial.unbound":  "(unbound)",
+	},
+}
@@ -0,0 +1,217 @@
+package main
+
+// localeID is the Indonesian catalog. Indonesian nouns do not inflect
+// for number, so plural messages only need the "other" form.
+var localeID = Locale{
+	Name:     "Bahasa Indonesia",
+	Plural:   pluralOther,
+	Decimal:  ",",
+	Suffixes: []string{"", "rb", "jt", "M", "T"}, // ribu, juta, miliar, triliun
+	Messages: map[string]string{
+		// Shop items
+		"item.Bed":         "Kasur",
+		"item.Door":        "Pintu",
+		"item.Playbox":     "Kotak Mainan",
+		"item.Trap":        "Jebakan",
+		"item.Guard":       "Penjaga",
+		"item.Pistol":      "Pistol",
+		"item.Rifle":       "Senapan",
+		"item.Shotgun":     "Senapan Tabur",
+		"item.Machine Gun": "Senapan Mesin",
+		"item.Sniper":      "Senapan Runduk",
+
+		"item.Auto Bed":     "Kasur Otomatis",
+		"item.Auto Door":    "Pintu Otomatis",
+		"item.Auto Playbox": "Kotak Mainan Otomatis",
+		"item.Auto Defense": "Pertahanan Otomatis",
+
+		"desc.autobuyer":      "Membeli %s setiap detik dengan sendirinya",
+		"desc.coins_per_s":    "+%s koin/dtk",
+		"desc.diamonds_per_s": "+%s berlian/dtk",
+		"desc.door_hp":        "+50 HP",
+		"desc.defense":        "+%d pertahanan",
+		"desc.gun":            "%d kerusakan, %s serangan/dtk",
+
+		"category.0": "KOIN",
+		"category.1": "BERLIAN",
+		"category.2": "SENJATA",
+		"category.3": "OTOMATIS",
+
+		"difficulty.easy":      "Mudah",
+		"difficulty.normal":    "Normal",
+		"difficulty.nightmare": "Mimpi Buruk",
+		"difficulty.custom":    "Kustom",
+
+		// Log messages, panels and dialogs
+		"log.hunter_defeated":          "Pemburu Mimpi dikalahkan! KAMU MENANG!",
+		"log.hunter_attacks":           "Pemburu menyerang pintu! -%d HP",
+		"log.door_broken":              "PERMAINAN BERAKHIR! Pintumu hancur!",
+		"log.hunter_spawned":           "Pemburu Mimpi Level %d muncul!",
+		"log.invalid_item":             "Barang tidak valid!",
+		"log.not_enough_resources":     "Sumber daya tidak cukup!",
+		"log.not_enough_coins":         "Koin tidak cukup!",
+		"log.bed_upgraded":             "Kasur naik ke level %d!",
+		"log.bed_upgraded_income":      "Kasur naik ke level %d! (+%s koin/dtk)",
+		"log.door_upgraded":            "Pintu naik ke level %d! (HP: %s)",
+		"log.playbox_upgraded":         "Kotak Mainan naik ke level %d!",
+		"log.playbox_upgraded_income":  "Kotak Mainan naik ke level %d! (+%s berlian/dtk)",
+		"log.trap_installed":           "Jebakan terpasang! Pertahanan +5",
+		"log.guard_hired":              "Penjaga disewa! Pertahanan +10",
+		"log.gun_purchased":            "%s dibeli! Kerusakan: %d, Kecepatan: %s/dtk",
+		"log.bought_batch":             "Membeli %s %s seharga %s",
+		"log.autobuyer_unlocked":       "%s terbuka!",
+		"log.autobuyer":                "%s:",
+		"log.sell_in_combat":           "Tidak bisa menjual saat pemburu menyerang!",
+		"log.cannot_sell":              "Barang ini tidak bisa dijual",
+		"log.sold":                     "%s terjual seharga %s",
+		"log.hosting":                  "Menjadi host di %s. Pemain bergabung dengan: %s join %s",
+		"log.player_joined":            "%s bergabung sebagai %s",
+		"log.player_left":              "%s keluar; %s kembali bermimpi sendiri",
+		"log.remote_bought":            "%s membeli %s",
+		"log.hunter_attacks_player":    "Pemburu menyerang pintu %s! -%d HP",
+		"log.player_eliminated":        "Pintu %s hancur! Dia keluar dari permainan.",
+		"log.max_level":                "%s sudah di level maksimal!",
+		"log.welcome":                  "Selamat datang di Haunted Room Defense!",
+		"log.welcome_goal":             "Lindungi kamarmu dari para Pemburu Mimpi!",
+		"log.welcome_tip":              "Beli kasur untuk menghasilkan koin!",
+		"log.resumed.other":            "Melanjutkan permainan tersimpan pada detik ke-%s",
+		"log.record_failed":            "Gagal mencatat permainan: %v",
+		"log.autopilot_off":            "Autopilot mati",
+		"log.export_failed":            "Gagal mengekspor log: %v",
+		"log.exported":                 "Log diekspor ke %s",
+		"log.effects_on":               "Efek pertempuran nyala",
+		"log.effects_off":              "Efek pertempuran mati",
+		"log.layout_failed":            "Gagal menyimpan tata letak: %v",
+		"log.layout":                   "Tata letak: %s",
+		"log.category.system":          "sistem",
+		"log.category.economy":         "ekonomi",
+		"log.category.combat":          "pertempuran",
+		"ui.autopilot":                 "Autopilot: %s",
+		"ui.paused":                    "JEDA",
+		"ui.speed":                     "Kecepatan: %d%s",
+		"ui.game_over":                 "PERMAINAN BERAKHIR",
+		"ui.items_hint":                "(%s: pindah, %s: tingkatkan, %s: jual)",
+		"ui.shop_hint":                 "(%s: kategori, %s: barang, %s: beli)",
+		"ui.buy":                       "Beli:",
+		"ui.buy_max":                   "maks",
+		"ui.sort":                      "Urut:",
+		"sort.default":                 "bawaan",
+		"sort.cost":                    "harga",
+		"sort.dps":                     "DPS/koin",
+		"sort.affordable":              "terjangkau",
+		"ui.eta.other":                 "terjangkau dalam %s detik",
+		"ui.eta_never":                 "tidak ada pemasukan untuk membelinya",
+		"ui.nothing_affordable":        "Belum ada yang terjangkau",
+		"ui.advisor":                   "Penasihat:",
+		"ui.advisor_save":              "menabung dulu",
+		"ui.cost_coins":                "%sk",
+		"ui.cost_diamonds":             "%sb",
+		"ui.cost_both":                 "%sk+%sb",
+		"ui.dreamers":                  "PEMIMPI",
+		"ui.you":                       "Kamu",
+		"ui.door_level":                "Pintu Lv%d",
+		"ui.room_items":                "BARANG KAMAR",
+		"ui.door":                      "Pintu:",
+		"ui.no_items":                  "Tidak ada barang",
+		"ui.hunter_level":              "PEMBURU LEVEL %d",
+		"ui.hunter_attack.other":       "Serangan: %d setiap %s detik",
+		"ui.hp":                        "HP:",
+		"ui.coins":                     "Koin: %s (+%s/dtk)",
+		"ui.diamonds":                  "Berlian: %s (+%s/dtk)",
+		"ui.defense":                   "Pertahanan: %d",
+		"ui.tutorial":                  "Tutorial:",
+		"ui.map_legend":                "%s utuh %s rusak %s hancur %s pemburu %s senjata",
+		"ui.sell_value":                "terjual seharga %s (refund %s%%)",
+		"ui.search":                    "Cari log: ",
+		"client.joining":               "Bergabung ke permainan di %s...",
+		"client.playing":               "Memainkan %s sebagai %s di %s",
+		"client.host":                  "Host",
+		"client.hunter":                "PEMBURU Lv%d di %s",
+		"client.no_hunter":             "Belum ada pemburu",
+		"client.guns":                  "Senjata: %d",
+		"client.shop":                  "TOKO",
+		"client.eliminated":            "Pintumu hancur. Kamu keluar sampai permainan berikutnya.",
+		"client.won":                   "Pemburu dikalahkan! Menunggu host memulai permainan baru.",
+		"client.lost":                  "Pintu host hancur! Menunggu host memulai permainan baru.",
+		"client.disconnected":          "Terputus dari host",
+		"owned.door":                   "Pintu Lv%d (HP:%s)",
+		"owned.bed":                    "Kasur Lv%d (+%s/dtk)",
+		"owned.playbox":                "Kotak Mainan Lv%d (+%s/dtk)",
+		"owned.defense":                "Pertahanan: %s",
+		"owned.autobuyer":              "%s: %s, cadangan %d%%",
+		"autobuyer.on":                 "nyala",
+		"autobuyer.off":                "mati",
+		"autobuyer.idle_only":          "hanya di antara pemburu",
+		"owned.gun":                    "%s (K:%d C:%s)",
+		"advice.gun":                   "+%s DPS, mengalahkan pemburu dalam %s dtk (pintu bertahan %s dtk)",
+		"advice.door":                  "+%s dtk sebelum pintu jebol (bertahan %s dtk, menang dalam %s dtk)",
+		"advice.producer":              "+%s %s, balik modal dalam %s dtk",
+		"unit.coins_per_s":             "koin/dtk",
+		"unit.diamonds_per_s":          "berlian/dtk",
+		"title.log":                    "Status & Log",
+		"title.dreamers":               "Pemimpi",
+		"title.room_items":             "Barang Kamar",
+		"title.your_items":             "Barangmu",
+		"title.map":                    "Denah Asrama",
+		"title.shop":                   "Toko",
+		"title.item_menu":              "Barang",
+		"title.client":                 "Haunted Room Defense",
+		"menu.upgrade":                 "Tingkatkan %s",
+		"menu.sell":                    "Jual seharga %s",
+		"menu.turn_on":                 "Nyalakan",
+		"menu.turn_off":                "Matikan",
+		"menu.reserve":                 "Cadangan %d%% (ubah)",
+		"menu.idle_only_on":            "Hanya di antara pemburu",
+		"menu.idle_only_off":           "Juga saat perburuan",
+		"menu.cancel":                  "Batal",
+		"modal.victory":                "MENANG!",
+		"modal.victory_text":           "Kamu mengalahkan Pemburu Mimpi!",
+		"modal.game_over":              "PERMAINAN BERAKHIR",
+		"modal.game_over_text":         "Pintumu dihancurkan!",
+		"modal.play_again":             "Main lagi? Pilih tingkat kesulitan atau pakai pengaturan ini:",
+		"modal.tutorial_complete":      "TUTORIAL SELESAI",
+		"modal.tutorial_complete_text": "Kamu mengalahkan Pemburu Mimpi pertamamu!",
+		"modal.real_game":              "Siap untuk permainan sungguhan? Pilih tingkat kesulitan:",
+		"button.same_settings":         "Pengaturan sama",
+		"button.quit":                  "Keluar",
+		"tab.Shop":                     "Toko",
+		"tab.Room":                     "Kamar",
+		"tab.Map":                      "Denah",
+		"tab.Log":                      "Log",
+		"tab.Dreamers":                 "Pemimpi",
+
+		// Help bar
+		"help.keys":        "Tombol:",
+		"help.Category":    "Kategori",
+		"help.Select":      "Pilih",
+		"help.Sell":        "Jual",
+		"help.Menu":        "Menu",
+		"help.Sort":        "Urut",
+		"help.Buy":         "Beli",
+		"help.BuyMode":     "ModeBeli",
+		"help.ItemNav":     "NavBarang",
+		"help.Upgrade":     "Tingkatkan",
+		"help.SpawnHunter": "PanggilPemburu",
+		"help.Autopilot":   "Autopilot",
+		"help.Advisor":     "Penasihat",
+		"help.Pause":       "Jeda",
+		"help.Speed":       "Kecepatan",
+		"help.Effects":     "Efek",
+		"help.LogFilter":   "FilterLog",
+		"help.Search":      "Cari",
+		"help.Export":      "Ekspor",
+		"help.Scroll":      "Gulir",
+		"help.Tabs":        "Tab",
+		"help.Layout":      "TataLetak",
+		"help.Quit":        "Keluar",
+
+		// Tutorial
+		"tutorial.step":     "Langkah %d/%d: %s",
+		"tutorial.bed":      "Beli Kasur: ada di kategori Koin di Toko (%s). Pilih dengan %s lalu tekan %s. Kasur menghasilkan koin setiap detik.",
+		"tutorial.door":     "Tingkatkan Pintumu: pilih di Barangmu dengan %s lalu tekan %s. Pintu yang lebih kuat bertahan lebih lama.",
+		"tutorial.pistol":   "Beli Pistol dari kategori Senjata (%s). Senjata menembak pemburu dengan sendirinya.",
+		"tutorial.fight":    "Pemburu Mimpi yang lemah datang! Pantau HP-nya di Barang Kamar dan jaga pintumu sampai senjatamu mengalahkannya.",
+		"tutorial.complete": "Tutorial selesai! Pilih tingkat kesulitan untuk memulai permainan sungguhan.",
+		"tutorial.unbound":  "(belum diatur)",
+	},
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+// Remote shop items, by the id clients buy them with
+const (
+	remoteBed    = "bed"
+	remoteDoor   = "door"
+	remotePistol = "pistol"
+)
+
+// Why a dreamer cannot be taken or an item cannot be bought
+var (
+	errNoDreamer    = errors.New("no such dreamer")
+	errDreamerTaken = errors.New("that dreamer is already taken")
+	errNoFreeSlot   = errors.New("every dreamer is taken")
+	errEliminated   = errors.New("your door is broken")
+	errNoSuchItem   = errors.New("no such item")
+	errMaxLevel     = errors.New("already at the highest level")
+	errCannotAfford = errors.New("not enough coins")
+)
+
+// controlled reports whether a networked player plays this dreamer
+func (c *Character) controlled() bool {
+	return c.player != ""
+}
+
+// dreamerIndex finds the dreamer called name, ignoring case
+func dreamerIndex(name string) (int, bool) {
+	for i, char := range gameState.rooms[0].characters {
+		if strings.EqualFold(char.name, name) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// TakeDreamer hands a dreamer to player, who from then on runs its
+// economy and shop instead of the script. An empty name takes the first
+// free dreamer. It returns the dreamer's index.
+func TakeDreamer(name, player string) (int, error) {
+	chars := gameState.rooms[0].characters
+	i := -1
+	if name == "" {
+		for j := range chars {
+			if !chars[j].controlled() && chars[j].doorHP > 0 {
+				i = j
+				break
+			}
+		}
+		if i < 0 {
+			return 0, errNoFreeSlot
+		}
+	} else {
+		j, ok := dreamerIndex(name)
+		if !ok {
+			return 0, errNoDreamer
+		}
+		if chars[j].controlled() {
+			return 0, errDreamerTaken
+		}
+		if chars[j].doorHP <= 0 {
+			return 0, errEliminated
+		}
+		i = j
+	}
+
+	char := &chars[i]
+	char.player = player
+	char.coins = 0
+	char.bedLevel = 1
+	char.guns = nil
+	return i, nil
+}
+
+// ReleaseDreamer gives dreamer i back to the script
+func ReleaseDreamer(i int) {
+	char := &gameState.rooms[0].characters[i]
+	char.player = ""
+	char.coins = 0
+	char.bedLevel = 0
+	char.guns = nil
+	char.lastUpgradeTime = timeNow()
+}
+
+// controlledTargets are the map rows of the played dreamers whose doors
+// still stand
+func controlledTargets() []int {
+	targets := []int{}
+	for i, char := range gameState.rooms[0].characters {
+		if char.controlled() && char.doorHP > 0 {
+			targets = append(targets, i+1)
+		}
+	}
+	return targets
+}
+
+// updateRemoteEconomy pays every played dreamer its bed income for one
+// second. Like yours, a level n bed makes 2^(n-1) coins per second.
+func updateRemoteEconomy() {
+	for i := range gameState.rooms[0].characters {
+		char := &gameState.rooms[0].characters[i]
+		if char.controlled() && char.doorHP > 0 {
+			char.coins += BigNum(int(1) << uint(char.bedLevel-1))
+		}
+	}
+}
+
+// RemoteShop is what a played dreamer can buy: its Bed and Door upgrades
+// and Pistols, priced like yours
+func RemoteShop(char *Character) []Item {
+	items := []Item{}
+	if char.bedLevel < 10 {
+		production := float64(int(1) << uint(char.bedLevel))
+		items = append(items, Item{
+			name:         "Bed",
+			currentLevel: char.bedLevel,
+			maxLevel:     10,
+			costCoins:    levelPrice("Bed", char.bedLevel),
+			production:   production,
+			description:  T("desc.coins_per_s", FormatNumber(production)),
+			itemType:     remoteBed,
+		})
+	}
+	if char.doorLevel < 10 {
+		items = append(items, Item{
+			name:         "Door",
+			currentLevel: char.doorLevel,
+			maxLevel:     10,
+			costCoins:    levelPrice("Door", char.doorLevel),
+			description:  T("desc.door_hp"),
+			itemType:     remoteDoor,
+		})
+	}
+	damage := GetGunDamage(len(char.guns) + 1)
+	items = append(items, Item{
+		name:         "Pistol",
+		currentLevel: len(char.guns),
+		maxLevel:     999,
+		costCoins:    levelPrice("Pistol", len(char.guns)),
+		damage:       damage,
+		attackSpeed:  1.0,
+		description:  T("desc.gun", damage, FormatDecimal(1.0, 1)),
+		itemType:     remotePistol,
+	})
+	return items
+}
+
+// BuyForDreamer buys the remote shop item with id itemID for played
+// dreamer i out of its own coins
+func BuyForDreamer(i int, itemID string, logPanel *tview.TextView) error {
+	char := &gameState.rooms[0].characters[i]
+	if char.doorHP <= 0 {
+		return errEliminated
+	}
+	var item *Item
+	for _, it := range RemoteShop(char) {
+		if it.itemType == itemID {
+			item = &it
+			break
+		}
+	}
+	if item == nil {
+		if itemID == remoteBed || itemID == remoteDoor {
+			return errMaxLevel
+		}
+		return errNoSuchItem
+	}
+	if item.costCoins > char.coins {
+		return errCannotAfford
+	}
+
+	char.coins -= item.costCoins
+	switch itemID {
+	case remoteBed:
+		char.bedLevel++
+	case remoteDoor:
+		char.doorLevel++
+		char.doorMaxHP = GetDoorHP(char.doorLevel)
+		char.doorHP = char.doorMaxHP
+	case remotePistol:
+		char.guns = append(char.guns, Gun{
+			name:        item.name,
+			level:       1,
+			damage:      item.damage,
+			attackSpeed: item.attackSpeed,
+			lastShot:    timeNow(),
+			paidCoins:   item.costCoins,
+		})
+	}
+	AddLog(logPanel, LogEconomy, "[cyan]"+T("log.remote_bought", tview.Escape(char.player), itemName(item.name))+"[white]")
+	emitEvent(Event{Type: EventPurchase, Item: item.name, Dreamer: char.name, Coins: float64(item.costCoins)})
+	return nil
+}
+
+// remoteGunsFire lets the guns of every played dreamer still standing
+// shoot the hunter. It reports whether the hunter died.
+func remoteGunsFire(now time.Time, logPanel *tview.TextView) bool {
+	for i := range gameState.rooms[0].characters {
+		char := &gameState.rooms[0].characters[i]
+		if !char.controlled() || char.doorHP <= 0 {
+			continue
+		}
+		for j := range char.guns {
+			if fireGun(&char.guns[j], char.name, now, logPanel) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// attackDreamer is the hunter's main attack landing on played dreamer
+// i's door. A broken door knocks that player out of the game.
+func attackDreamer(i int, logPanel *tview.TextView) {
+	char := &gameState.rooms[0].characters[i]
+	gameState.hunterPos = i + 1
+	char.doorHP = max(char.doorHP-gameState.hunterAttack, 0)
+	AddLog(logPanel, LogCombat, "[red]"+T("log.hunter_attacks_player", tview.Escape(char.player), gameState.hunterAttack)+"[white]")
+	emitEvent(Event{Type: EventDreamerHit, Dreamer: char.name, Damage: gameState.hunterAttack, DoorHP: intPtr(char.doorHP)})
+	if char.doorHP == 0 {
+		AddLog(logPanel, LogCombat, "[red]"+T("log.player_eliminated", tview.Escape(char.player))+"[white]")
+	}
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// buildPurchase is one purchase made while replaying a build order
+type buildPurchase struct {
+	at       time.Duration
+	name     string
+	coins    BigNum
+	diamonds BigNum
+}
+
+// sequenceStrategy buys shop items strictly in the given order, waiting
+// until each one is affordable. Items that have left the shop (for
+// example a maxed Bed) are skipped.
+type sequenceStrategy struct {
+	order     []string
+	next      int
+	purchases []buildPurchase
+}
+
+func (s *sequenceStrategy) Name() string { return "sequence" }
+
+func (s *sequenceStrategy) Decide(snap Snapshot) []Action {
+	for s.next < len(s.order) {
+		category, index, item, ok := findShopItem(snap, s.order[s.next])
+		if !ok {
+			s.next++
+			continue
+		}
+		if !snap.CanAfford(item) {
+			break
+		}
+		s.next++
+		s.purchases = append(s.purchases, buildPurchase{
+			at:       gameState.elapsed,
+			name:     item.name,
+			coins:    item.costCoins,
+			diamonds: item.costDiamonds,
+		})
+		return []Action{{Kind: ActionBuy, Category: category, Index: index}}
+	}
+	return []Action{{Kind: ActionWait}}
+}
+
+// findShopItem finds the current offering called name in any category
+func findShopItem(snap Snapshot, name string) (int, int, Item, bool) {
+	for category, items := range snap.Shop {
+		for i, item := range items {
+			if item.name == name {
+				return category, i, item, true
+			}
+		}
+	}
+	return 0, 0, Item{}, false
+}
+
+// shopCatalog returns the names of every item a new game offers
+func shopCatalog() []string {
+	InitGame()
+	names := []string{}
+	for _, items := range TakeSnapshot().Shop {
+		for _, item := range items {
+			names = append(names, item.name)
+		}
+	}
+	return names
+}
+
+// buildCandidate is a purchase order and how well it did over the
+// evaluated games
+type buildCandidate struct {
+	order   []string
+	fitness float64 // mean over the games; lower is better
+	wins    int
+	seconds float64 // mean time to win of the won games
+	slowest float64 // slowest win
+}
+
+// RunOptimize implements the optimize subcommand. It runs a genetic search
+// over purchase orders and prints the fastest build that beats the hunter.
+func RunOptimize(args []string) int {
+	fs := flag.NewFlagSet("optimize", flag.ContinueOnError)
+	level := fs.Int("level", 1, "level of the hunter to beat")
+	seed := fs.Int64("seed", 1, "seed for the search and the first evaluated game; game i uses seed+i")
+	games := fs.Int("games", 3, "games to average every build order over")
+	population := fs.Int("population", 40, "build orders per generation")
+	generations := fs.Int("generations", 60, "number of generations to evolve")
+	length := fs.Int("length", 16, "maximum number of purchases in a build order")
+	maxSeconds := fs.Int("max", 600, "give up on a game after this many game seconds")
+	difficultyName := fs.String("difficulty", "normal", "difficulty preset: easy, normal, nightmare or custom:key=value,...")
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	d, err := ParseDifficulty(*difficultyName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "optimize: %v\n", err)
+		return 2
+	}
+	SetDifficulty(d)
+	if *level < 1 || *games < 1 || *population < 2 || *generations < 1 || *length < 1 || *maxSeconds < 1 {
+		fmt.Fprintln(os.Stderr, "optimize: -level, -games, -generations, -length and -max must be positive and -population at least 2")
+		return 2
+	}
+
+	config := simConfig{MaxSeconds: *maxSeconds, HunterLevel: *level}
+	catalog := shopCatalog()
+	rng := rand.New(rand.NewSource(*seed))
+
+	// A build is scored on several seeds, whose hunters spawn at different
+	// times, so one lucky game cannot win
+	evaluate := func(order []string) buildCandidate {
+		c := buildCandidate{order: order}
+		for i := 0; i < *games; i++ {
+			result := RunSimGame(*seed+int64(i), &sequenceStrategy{order: order}, config)
+			fitness := result.Seconds
+			if result.Won {
+				c.wins++
+				c.seconds += result.Seconds
+				c.slowest = max(c.slowest, result.Seconds)
+			} else {
+				// Losing games rank behind every win, closer losses first
+				hunterHP := float64(GetHunterHP(*level))
+				fitness = float64(*maxSeconds) * (2 + float64(result.HunterHPLeft)/hunterHP)
+			}
+			c.fitness += fitness / float64(*games)
+		}
+		if c.wins > 0 {
+			c.seconds /= float64(c.wins)
+		}
+		return c
+	}
+
+	pool := []buildCandidate{}
+	for i := 0; i < *population; i++ {
+		order := make([]string, 1+rng.Intn(*length))
+		for j := range order {
+			order[j] = catalog[rng.Intn(len(catalog))]
+		}
+		pool = append(pool, evaluate(order))
+	}
+
+	for gen := 0; gen < *generations; gen++ {
+		sort.SliceStable(pool, func(i, j int) bool { return pool[i].fitness < pool[j].fitness })
+
+		// Keep the best quarter and breed the rest from it
+		elite := *population / 4
+		if elite < 1 {
+			elite = 1
+		}
+		next := append([]buildCandidate{}, pool[:elite]...)
+		for len(next) < *population {
+			a := pool[rng.Intn(elite)].order
+			b := pool[rng.Intn(len(pool)/2+1)].order
+			child := crossoverBuild(rng, a, b, *length)
+			child = mutateBuild(rng, child, catalog, *length)
+			next = append(next, evaluate(child))
+		}
+		pool = next
+	}
+	sort.SliceStable(pool, func(i, j int) bool { return pool[i].fitness < pool[j].fitness })
+
+	best := pool[0]
+	if best.wins == 0 {
+		fmt.Printf("No winning build found against hunter level %d within %ds\n", *level, *maxSeconds)
+		return 1
+	}
+
+	// Replay the winner on the first seed to record its timeline
+	replay := &sequenceStrategy{order: best.order}
+	RunSimGame(*seed, replay, config)
+
+	fmt.Printf("Fastest build vs hunter level %d (%s): won %d of %d games in %.1fs on average, %.1fs at worst\n", *level, d.Name, best.wins, *games, best.seconds, best.slowest)
+	fmt.Printf("Timeline of the game with seed %d:\n\n", *seed)
+	counts := map[string]int{}
+	for _, p := range replay.purchases {
+		cost := p.coins.String() + "c"
+		if p.diamonds > 0 {
+			cost += "+" + p.diamonds.String() + "d"
+		}
+		fmt.Printf("  t=%6.1fs  %-12s %s\n", p.at.Seconds(), p.name, cost)
+		counts[p.name]++
+	}
+
+	mix := []string{}
+	for name, n := range counts {
+		mix = append(mix, fmt.Sprintf("%s x%d", name, n))
+	}
+	sort.Strings(mix)
+	fmt.Printf("\nItem mix: %s\n", strings.Join(mix, ", "))
+	return 0
+}
+
+// crossoverBuild joins a prefix of a with a suffix of b
+func crossoverBuild(rng *rand.Rand, a, b []string, maxLen int) []string {
+	cutA := rng.Intn(len(a) + 1)
+	cutB := rng.Intn(len(b) + 1)
+	child := append(append([]string{}, a[:cutA]...), b[cutB:]...)
+	if len(child) > maxLen {
+		child = child[:maxLen]
+	}
+	return child
+}
+
+// mutateBuild randomly replaces, inserts or removes one purchase
+func mutateBuild(rng *rand.Rand, order []string, catalog []string, maxLen int) []string {
+	gene := catalog[rng.Intn(len(catalog))]
+	switch op := rng.Intn(3); {
+	case op == 0 && len(order) > 0:
+		order[rng.Intn(len(order))] = gene
+	case op == 1 && len(order) < maxLen:
+		at := rng.Intn(len(order) + 1)
+		order = append(order[:at], append([]string{gene}, order[at:]...)...)
+	case op == 2 && len(order) > 1:
+		at := rng.Intn(len(order))
+		order = append(order[:at], order[at+1:]...)
+	}
+	return order
+}
@@ -0,0 +1,21 @@
+//go:build !linux
+
+package main
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+)
+
+var errNoPTY = errors.New("serving over SSH needs Linux pseudo-terminals")
+
+// startPTY is only available on Linux
+func startPTY(cmd *exec.Cmd, cols, rows int) (*os.File, error) {
+	return nil, errNoPTY
+}
+
+// resizePTY is only available on Linux
+func resizePTY(master *os.File, cols, rows int) error {
+	return errNoPTY
+}
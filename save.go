@@ -0,0 +1,281 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+// RecordedAction is an applied action stamped with the game time it
+// happened at
+type RecordedAction struct {
+	At       time.Duration `json:"at"`
+	Kind     ActionKind    `json:"kind"`
+	Category int           `json:"category"`
+	Index    int           `json:"index"`
+	Count    int           `json:"count,omitempty"` // batch size of a buy
+}
+
+// Recording is everything needed to rebuild a game. The engine is
+// deterministic given the seed, the difficulty and the action timeline,
+// so recordings double as save files and replays.
+type Recording struct {
+	Seed       int64            `json:"seed"`
+	Difficulty Difficulty       `json:"difficulty"`
+	Actions    []RecordedAction `json:"actions"`
+	Elapsed    time.Duration    `json:"elapsed"`
+	Over       bool             `json:"over"`
+	Won        bool             `json:"won"`
+	SavedAt    time.Time        `json:"saved_at"`
+}
+
+// recording collects the actions of the game being played, or is nil
+var recording *Recording
+
+// StartRecording begins a new game with seed on the current difficulty
+// and records every action applied to it
+func StartRecording(seed int64) {
+	InitGameWithSeed(seed)
+	recording = &Recording{Seed: seed, Difficulty: difficulty}
+}
+
+// recordAction appends action to the current recording, if any
+func recordAction(action Action) {
+	if recording == nil {
+		return
+	}
+	recording.Actions = append(recording.Actions, RecordedAction{
+		At:       gameState.elapsed,
+		Kind:     action.Kind,
+		Category: action.Category,
+		Index:    action.Index,
+		Count:    action.Count,
+	})
+}
+
+// snapshotRecording stamps the recording with the current game progress
+func snapshotRecording() *Recording {
+	rec := *recording
+	rec.Actions = append([]RecordedAction{}, recording.Actions...)
+	rec.Elapsed = gameState.elapsed
+	rec.Over = gameState.gameOver
+	rec.Won = gameState.gameWon
+	rec.SavedAt = time.Now()
+	return &rec
+}
+
+// recordedDifficulty is a recording's difficulty, with the refund rate of
+// its preset filled in for recordings from before selling existed
+func recordedDifficulty(d Difficulty) Difficulty {
+	if d.SellRefund == 0 {
+		d.SellRefund = difficultyPresets["normal"].SellRefund
+		if preset, ok := difficultyPresets[d.Name]; ok {
+			d.SellRefund = preset.SellRefund
+		}
+	}
+	return d
+}
+
+// ReplayRecording rebuilds the game in rec up to rec.Elapsed. Each
+// action is applied right after the engine step it was recorded at,
+// which is where the TUI applied it too. onAction, if set, is called
+// just before every replayed action.
+func ReplayRecording(rec *Recording, logPanel *tview.TextView, onAction func(RecordedAction)) {
+	recording = nil
+	rec.Difficulty = recordedDifficulty(rec.Difficulty)
+	SetDifficulty(rec.Difficulty)
+	InitGameWithSeed(rec.Seed)
+
+	next := 0
+	applyDue := func() {
+		for next < len(rec.Actions) && rec.Actions[next].At <= gameState.elapsed {
+			a := rec.Actions[next]
+			if onAction != nil {
+				onAction(a)
+			}
+			ApplyAction(Action{Kind: a.Kind, Category: a.Category, Index: a.Index, Count: a.Count}, logPanel)
+			next++
+		}
+	}
+
+	applyDue()
+	for gameState.elapsed < rec.Elapsed && !gameState.gameOver {
+		StepGame(logPanel)
+		applyDue()
+	}
+}
+
+// validateSlot rejects slot names that are not safe as a directory name
+func validateSlot(slot string) error {
+	if slot == "" || strings.ContainsAny(slot, `/\.`) {
+		return fmt.Errorf("invalid save slot %q", slot)
+	}
+	return nil
+}
+
+// slotDir is the directory holding a save slot's files
+func slotDir(configPath, slot string) string {
+	return filepath.Join(filepath.Dir(configPath), "slots", slot)
+}
+
+// readRecording reads the recording at path and checks its actions
+func readRecording(path string) (*Recording, error) {
+	rec := &Recording{}
+	if err := readJSONFile(path, rec); err != nil {
+		return nil, err
+	}
+	if err := rec.check(); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return rec, nil
+}
+
+// check rejects actions no game could have applied: unknown kinds,
+// negative counts and items outside every listing
+func (rec *Recording) check() error {
+	shop := fullShop()
+	for i, a := range rec.Actions {
+		ok := a.Index >= 0 && a.Count >= 0
+		switch a.Kind {
+		case ActionBuy:
+			ok = ok && a.Category >= 0 && a.Category < len(shop) && a.Index < len(shop[a.Category])
+		case ActionAutoBuyerToggle, ActionAutoBuyerReserve, ActionAutoBuyerIdle:
+			ok = ok && a.Index < len(autoBuyerKinds)
+		case ActionUpgrade, ActionSell, ActionSpawn:
+		default:
+			return fmt.Errorf("action %d: unknown kind %d", i+1, a.Kind)
+		}
+		if !ok {
+			return fmt.Errorf("action %d: %s of item %d in category %d is out of range", i+1, a.Kind, a.Index, a.Category)
+		}
+	}
+	return nil
+}
+
+// fullShop is the shop at the start of a game. Items only drop out of
+// it later, so it lists everything that can ever be bought.
+func fullShop() [][]Item {
+	saved := gameState
+	defer func() { gameState = saved }()
+	InitGameWithSeed(0)
+	return TakeSnapshot().Shop
+}
+
+// LoadSave reads the unfinished game in a slot. It returns nil if the
+// slot has no save.
+func LoadSave(dir string) (*Recording, error) {
+	rec, err := readRecording(filepath.Join(dir, "save.json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+// WriteSave stores the game in progress so the slot can resume it
+func WriteSave(dir string) error {
+	if recording == nil || gameState.gameOver {
+		return nil
+	}
+	return writeJSONFile(filepath.Join(dir, "save.json"), snapshotRecording())
+}
+
+// FinishGame records a finished game in the slot: it writes a replay,
+// updates the stats and removes the save
+func FinishGame(dir string) error {
+	if recording == nil || !gameState.gameOver {
+		return nil
+	}
+	rec := snapshotRecording()
+	recording = nil
+
+	// Names sort by time; games finished in the same instant get a suffix
+	stamp := rec.SavedAt.Format("20060102-150405.000000")
+	path := filepath.Join(dir, "replays", stamp+".json")
+	for n := 2; ; n++ {
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			break
+		}
+		path = filepath.Join(dir, "replays", fmt.Sprintf("%s_%d.json", stamp, n))
+	}
+	if err := writeJSONFile(path, rec); err != nil {
+		return err
+	}
+
+	stats, err := LoadStats(dir)
+	if err != nil {
+		return err
+	}
+	stats.Add(rec)
+	if err := writeJSONFile(filepath.Join(dir, "stats.json"), stats); err != nil {
+		return err
+	}
+
+	err = os.Remove(filepath.Join(dir, "save.json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// LatestReplay returns the path of the newest replay in a slot
+func LatestReplay(dir string) (string, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "replays", "*.json"))
+	if err != nil {
+		return "", err
+	}
+	if len(matches) == 0 {
+		return "", fmt.Errorf("no replays in %s", dir)
+	}
+	sort.Strings(matches)
+	return matches[len(matches)-1], nil
+}
+
+// DifficultyStats are the results for one difficulty preset
+type DifficultyStats struct {
+	Games          int     `json:"games"`
+	Wins           int     `json:"wins"`
+	BestWinSeconds float64 `json:"best_win_seconds"`
+	TotalSeconds   float64 `json:"total_seconds"`
+}
+
+// SlotStats are the lifetime results of a save slot, per difficulty
+type SlotStats struct {
+	ByDifficulty map[string]DifficultyStats `json:"by_difficulty"`
+}
+
+// LoadStats reads a slot's stats, or empty stats if there are none yet
+func LoadStats(dir string) (*SlotStats, error) {
+	stats := &SlotStats{ByDifficulty: map[string]DifficultyStats{}}
+	err := readJSONFile(filepath.Join(dir, "stats.json"), stats)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if stats.ByDifficulty == nil {
+		stats.ByDifficulty = map[string]DifficultyStats{}
+	}
+	return stats, nil
+}
+
+// Add counts a finished game
+func (s *SlotStats) Add(rec *Recording) {
+	d := s.ByDifficulty[rec.Difficulty.Name]
+	d.Games++
+	seconds := rec.Elapsed.Seconds()
+	d.TotalSeconds += seconds
+	if rec.Won {
+		d.Wins++
+		if d.BestWinSeconds == 0 || seconds < d.BestWinSeconds {
+			d.BestWinSeconds = seconds
+		}
+	}
+	s.ByDifficulty[rec.Difficulty.Name] = d
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// playRecorded plays greedy on a recorded game for up to limit
+func playRecorded(seed int64, limit time.Duration) *Recording {
+	StartRecording(seed)
+	for gameState.elapsed < limit && !gameState.gameOver {
+		StepGame(nil)
+		RunStrategyStep(greedyStrategy{}, nil)
+	}
+	rec := snapshotRecording()
+	recording = nil
+	return rec
+}
+
+func TestRecordingRoundTrip(t *testing.T) {
+	SetDifficulty(difficultyPresets["normal"])
+	for _, limit := range []time.Duration{10 * time.Second, 10 * time.Minute} {
+		rec := playRecorded(3, limit)
+		coins, guns := gameState.coins, len(gameState.guns)
+		if len(rec.Actions) == 0 {
+			t.Fatalf("%v: greedy recorded no actions", limit)
+		}
+
+		path := filepath.Join(t.TempDir(), "replay.json")
+		if err := writeJSONFile(path, rec); err != nil {
+			t.Fatal(err)
+		}
+		loaded, err := readRecording(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ReplayRecording(loaded, nil, nil)
+		if gameState.elapsed != rec.Elapsed || gameState.gameOver != rec.Over || gameState.gameWon != rec.Won {
+			t.Errorf("%v: replay ended at %v (over %v, won %v), recording at %v (over %v, won %v)", limit,
+				gameState.elapsed, gameState.gameOver, gameState.gameWon, rec.Elapsed, rec.Over, rec.Won)
+		}
+		if gameState.coins != coins || len(gameState.guns) != guns {
+			t.Errorf("%v: replay has %v coins and %d guns, the game had %v and %d", limit, gameState.coins, len(gameState.guns), coins, guns)
+		}
+	}
+}
+
+func TestRecordingCheck(t *testing.T) {
+	SetDifficulty(difficultyPresets["normal"])
+	tests := []struct {
+		name   string
+		action RecordedAction
+		want   string // part of the error, or "" for none
+	}{
+		{"buy", RecordedAction{Kind: ActionBuy, Category: 2, Index: 4, Count: 3}, ""},
+		{"sell", RecordedAction{Kind: ActionSell, Index: 7}, ""},
+		{"unknown kind", RecordedAction{Kind: 99}, "unknown kind 99"},
+		{"wait", RecordedAction{Kind: ActionWait}, "unknown kind 0"},
+		{"category", RecordedAction{Kind: ActionBuy, Category: shopCategories}, "out of range"},
+		{"shop index", RecordedAction{Kind: ActionBuy, Category: 2, Index: 5}, "out of range"},
+		{"negative index", RecordedAction{Kind: ActionUpgrade, Index: -1}, "out of range"},
+		{"negative count", RecordedAction{Kind: ActionBuy, Count: BuyMax}, "out of range"},
+		{"auto-buyer", RecordedAction{Kind: ActionAutoBuyerToggle, Index: len(autoBuyerKinds)}, "out of range"},
+	}
+	for _, tt := range tests {
+		rec := &Recording{Actions: []RecordedAction{tt.action}}
+		err := rec.check()
+		if tt.want == "" && err != nil || tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)) {
+			t.Errorf("%s: got %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
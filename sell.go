@@ -0,0 +1,102 @@
+package main
+
+import "github.com/rivo/tview"
+
+// Kinds of Your Items rows, in the order updateItemsPanelList lists them
+const (
+	ownedDoor      = "door"
+	ownedBed       = "bed"
+	ownedPlaybox   = "playbox"
+	ownedDefense   = "defense"
+	ownedGun       = "gun"
+	ownedAutoBuyer = "autobuyer"
+)
+
+// ownedRow tells what row of the Your Items panel shows. For guns and
+// auto-buyers index is their position in gameState.guns or autoBuyers.
+func ownedRow(row int) (kind string, index int) {
+	rows := []string{ownedDoor}
+	if gameState.bedLevel > 0 {
+		rows = append(rows, ownedBed)
+	}
+	if gameState.playboxLevel > 0 {
+		rows = append(rows, ownedPlaybox)
+	}
+	rows = append(rows, ownedDefense)
+	if row < len(rows) {
+		return rows[row], 0
+	}
+	if gun := row - len(rows); gun < len(gameState.guns) {
+		return ownedGun, gun
+	}
+	if buyer, ok := autoBuyerRow(row); ok {
+		return ownedAutoBuyer, buyer
+	}
+	return "", 0
+}
+
+// SellValue is what selling Your Items row would refund: the difficulty's
+// share of a gun's price, or of the last level of the Bed, Door or
+// Playbox. Bed and Door cannot go below level 1; defense and auto-buyers
+// cannot be sold.
+func SellValue(row int) (coins, diamonds BigNum, ok bool) {
+	refund := BigNum(difficulty.SellRefund)
+	switch kind, index := ownedRow(row); kind {
+	case ownedGun:
+		gun := gameState.guns[index]
+		return gun.paidCoins * refund, gun.paidDiamonds * refund, true
+	case ownedBed:
+		if gameState.bedLevel > 1 {
+			return levelPrice("Bed", gameState.bedLevel-1) * refund, 0, true
+		}
+	case ownedDoor:
+		if gameState.doorLevel > 1 {
+			return levelPrice("Door", gameState.doorLevel-1) * refund, 0, true
+		}
+	case ownedPlaybox:
+		return levelPrice("Playbox", gameState.playboxLevel-1) * refund, 0, true
+	}
+	return 0, 0, false
+}
+
+// SellItem sells Your Items row for its SellValue. Nothing can be sold
+// while a hunter is attacking.
+func SellItem(row int, logPanel *tview.TextView) bool {
+	if gameState.hunterActive {
+		AddLog(logPanel, LogEconomy, "[red]"+T("log.sell_in_combat")+"[white]")
+		return false
+	}
+	coins, diamonds, ok := SellValue(row)
+	if !ok {
+		AddLog(logPanel, LogEconomy, "[yellow]"+T("log.cannot_sell")+"[white]")
+		return false
+	}
+
+	name := ""
+	switch kind, index := ownedRow(row); kind {
+	case ownedGun:
+		name = gameState.guns[index].name
+		gameState.guns = append(gameState.guns[:index], gameState.guns[index+1:]...)
+	case ownedBed:
+		name = "Bed"
+		gameState.bedLevel--
+	case ownedDoor:
+		name = "Door"
+		gameState.doorLevel--
+		gameState.doorMaxHP = GetDoorHP(gameState.doorLevel)
+		gameState.doorHP = min(gameState.doorHP, gameState.doorMaxHP)
+	case ownedPlaybox:
+		name = "Playbox"
+		gameState.playboxLevel--
+	}
+	gameState.coins += coins
+	gameState.diamonds += diamonds
+
+	AddLog(logPanel, LogEconomy, "[yellow]"+T("log.sold", itemName(name), costString(coins, diamonds))+"[white]")
+	emitEvent(Event{Type: EventSell, Item: name, Coins: float64(coins), Diamonds: float64(diamonds)})
+	updateItemsPanelList()
+	if gameState.itemsPanelSelected >= len(gameState.itemsPanelItems) {
+		gameState.itemsPanelSelected = len(gameState.itemsPanelItems) - 1
+	}
+	return true
+}
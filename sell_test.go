@@ -0,0 +1,21 @@
+package main
+
+import "testing"
+
+func TestSellValueRefundsLastLevel(t *testing.T) {
+	SetDifficulty(difficultyPresets["hard"])
+	for row, name := range []string{"Door", "Bed", "Playbox"} {
+		InitGameWithSeed(1)
+		gameState.coins = 1e9
+		var paid BigNum
+		for k := 0; k < 3; k++ {
+			category, index, item := findItem(t, name)
+			paid = item.costCoins
+			BuyItemByCategory(index, category, nil)
+		}
+		coins, _, ok := SellValue(row)
+		if want := paid * BigNum(difficulty.SellRefund); !ok || coins != want {
+			t.Errorf("%s: sells for %v (%v), want %v", name, coins, ok, want)
+		}
+	}
+}
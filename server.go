@@ -0,0 +1,368 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rivo/tview"
+)
+
+// netMessage is one line of the multiplayer protocol: newline-delimited
+// JSON over TCP. Clients send "join" and "buy"; the host answers with
+// "welcome" or "error" and sends a "state" after every game tick.
+type netMessage struct {
+	Type    string    `json:"type"`
+	Name    string    `json:"name,omitempty"`    // join: player name
+	Dreamer string    `json:"dreamer,omitempty"` // join: wanted dreamer, empty for any; welcome: the one taken
+	Item    string    `json:"item,omitempty"`    // buy: bed, door or pistol
+	Error   string    `json:"error,omitempty"`
+	State   *netState `json:"state,omitempty"`
+}
+
+// netState is the game as one player sees it
+type netState struct {
+	Time   float64    `json:"t"`
+	Hunter netHunter  `json:"hunter"`
+	Doors  []netDoor  `json:"doors"` // yours first, then the dreamers'
+	You    *netPlayer `json:"you,omitempty"`
+	Over   bool       `json:"over"`
+	Won    bool       `json:"won"`
+}
+
+type netHunter struct {
+	Active bool   `json:"active"`
+	Level  int    `json:"level"`
+	HP     int    `json:"hp"`
+	MaxHP  int    `json:"max_hp"`
+	Target string `json:"target,omitempty"` // name of the door under attack
+}
+
+type netDoor struct {
+	Name   string `json:"name"`
+	Player string `json:"player,omitempty"`
+	Level  int    `json:"level"`
+	HP     int    `json:"hp"`
+	MaxHP  int    `json:"max_hp"`
+}
+
+type netPlayer struct {
+	Dreamer   string    `json:"dreamer"`
+	Coins     float64   `json:"coins"`
+	CoinsPerS float64   `json:"coins_per_s"`
+	BedLevel  int       `json:"bed_level"`
+	Guns      int       `json:"guns"`
+	Shop      []netItem `json:"shop"`
+}
+
+type netItem struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	Level       int     `json:"level"`
+	Cost        float64 `json:"cost"`
+	Description string  `json:"description"`
+}
+
+// netClient is one connected player
+type netClient struct {
+	conn    net.Conn
+	in      chan netMessage // messages waiting for Sync, closed when it leaves
+	out     chan []byte
+	name    string
+	dreamer string // dreamer it plays, empty until it joins
+}
+
+// Server hosts a game for networked players. It only touches the game
+// from Sync, which the UI goroutine calls between ticks.
+type Server struct {
+	ln       net.Listener
+	connects chan *netClient // clients waiting for Sync to add them
+
+	mu      sync.Mutex
+	clients map[*netClient]bool
+
+	game *GameState // game the players are seated in
+}
+
+// hostServer is the running multiplayer server, or nil when not hosting
+var hostServer *Server
+
+// maxPlayerName is the longest player name, in characters
+const maxPlayerName = 16
+
+// maxCommandsPerTick caps the client messages Sync applies in one tick, so
+// a flood of them cannot stall the game; the rest wait for the next tick
+const maxCommandsPerTick = 32
+
+// maxClientQueue is how many messages of one client may wait for Sync.
+// A client sending faster is no longer read from until Sync catches up,
+// which slows only that client down.
+const maxClientQueue = 16
+
+// Server-side message types for connecting and dropping clients
+const (
+	netConnect = "connect"
+	netLeave   = "leave"
+)
+
+// Listen starts a server on addr, e.g. "localhost:7777"
+func Listen(addr string) (*Server, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{ln: ln, connects: make(chan *netClient, 16), clients: map[*netClient]bool{}}
+	go s.accept()
+	return s, nil
+}
+
+// Addr is the address the server listens on
+func (s *Server) Addr() string {
+	return s.ln.Addr().String()
+}
+
+// Close stops accepting players and disconnects everyone
+func (s *Server) Close() error {
+	if s == nil {
+		return nil
+	}
+	err := s.ln.Close()
+	s.mu.Lock()
+	for c := range s.clients {
+		c.conn.Close()
+	}
+	s.mu.Unlock()
+	return err
+}
+
+func (s *Server) accept() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		c := &netClient{conn: conn, in: make(chan netMessage, maxClientQueue), out: make(chan []byte, 16)}
+		s.connects <- c
+		go s.write(c)
+		go s.read(c)
+	}
+}
+
+// read queues the client's messages until it disconnects
+func (s *Server) read(c *netClient) {
+	defer close(c.in)
+	scanner := bufio.NewScanner(c.conn)
+	for scanner.Scan() {
+		var msg netMessage
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			msg = netMessage{Type: "invalid"}
+		}
+		c.in <- msg
+	}
+}
+
+// write sends the client's outgoing messages until it is dropped
+func (s *Server) write(c *netClient) {
+	for line := range c.out {
+		if _, err := c.conn.Write(line); err != nil {
+			c.conn.Close()
+		}
+	}
+	c.conn.Close()
+}
+
+// send queues msg for c. A client too slow to keep up misses messages
+// rather than holding up the game.
+func (c *netClient) send(msg netMessage) {
+	line, _ := json.Marshal(msg)
+	select {
+	case c.out <- append(line, '\n'):
+	default:
+	}
+}
+
+// Sync applies the queued client messages and sends every player the new
+// state. It runs on the UI goroutine once per tick. Clients take turns
+// a message at a time, so one flooding the server cannot starve the rest.
+func (s *Server) Sync(logPanel *tview.TextView) {
+	if s == nil {
+		return
+	}
+
+	// A restarted game seats everyone again, in the same dreamers if free
+	if s.game != gameState {
+		s.game = gameState
+		for c := range s.clients {
+			if c.dreamer == "" {
+				continue
+			}
+			i, err := TakeDreamer(c.dreamer, c.name)
+			if err != nil {
+				i, err = TakeDreamer("", c.name)
+			}
+			if err != nil {
+				c.dreamer = ""
+				c.send(netMessage{Type: "error", Error: err.Error()})
+				continue
+			}
+			c.dreamer = gameState.rooms[0].characters[i].name
+			c.send(netMessage{Type: "welcome", Dreamer: c.dreamer})
+		}
+	}
+
+	for pending := true; pending; {
+		select {
+		case c := <-s.connects:
+			s.handle(c, netMessage{Type: netConnect}, logPanel)
+		default:
+			pending = false
+		}
+	}
+
+	for handled, pending := 0, true; pending && handled < maxCommandsPerTick; {
+		pending = false
+		for c := range s.clients {
+			if handled == maxCommandsPerTick {
+				break
+			}
+			select {
+			case msg, ok := <-c.in:
+				if !ok {
+					msg = netMessage{Type: netLeave}
+				}
+				s.handle(c, msg, logPanel)
+				handled++
+				pending = true
+			default:
+			}
+		}
+	}
+
+	for c := range s.clients {
+		c.send(netMessage{Type: "state", State: s.state(c)})
+	}
+}
+
+// handle applies one client message to the game
+func (s *Server) handle(c *netClient, msg netMessage, logPanel *tview.TextView) {
+	switch msg.Type {
+	case netConnect:
+		s.mu.Lock()
+		s.clients[c] = true
+		s.mu.Unlock()
+	case netLeave:
+		if i, ok := s.seat(c); ok {
+			ReleaseDreamer(i)
+			AddLog(logPanel, LogSystem, "[yellow]"+T("log.player_left", tview.Escape(c.name), c.dreamer)+"[white]")
+		}
+		s.mu.Lock()
+		delete(s.clients, c)
+		s.mu.Unlock()
+		close(c.out)
+	case "join":
+		name := strings.TrimSpace(msg.Name)
+		switch {
+		case c.dreamer != "":
+			c.send(netMessage{Type: "error", Error: "already joined"})
+			return
+		case name == "":
+			c.send(netMessage{Type: "error", Error: "a player name is required"})
+			return
+		case !validPlayerName(name):
+			c.send(netMessage{Type: "error", Error: "a player name is at most 16 printable characters"})
+			return
+		}
+		i, err := TakeDreamer(msg.Dreamer, name)
+		if err != nil {
+			c.send(netMessage{Type: "error", Error: err.Error()})
+			return
+		}
+		c.name = name
+		c.dreamer = gameState.rooms[0].characters[i].name
+		c.send(netMessage{Type: "welcome", Dreamer: c.dreamer})
+		AddLog(logPanel, LogSystem, "[green]"+T("log.player_joined", tview.Escape(c.name), c.dreamer)+"[white]")
+	case "buy":
+		i, ok := s.seat(c)
+		if !ok {
+			c.send(netMessage{Type: "error", Error: "join the game first"})
+			return
+		}
+		if err := BuyForDreamer(i, msg.Item, logPanel); err != nil {
+			c.send(netMessage{Type: "error", Error: err.Error()})
+		}
+	default:
+		c.send(netMessage{Type: "error", Error: "unknown message"})
+	}
+}
+
+// validPlayerName reports whether name is short enough and printable
+func validPlayerName(name string) bool {
+	if !utf8.ValidString(name) || utf8.RuneCountInString(name) > maxPlayerName {
+		return false
+	}
+	for _, r := range name {
+		if !unicode.IsPrint(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// seat finds the dreamer c plays in the current game
+func (s *Server) seat(c *netClient) (int, bool) {
+	if c.dreamer == "" {
+		return 0, false
+	}
+	i, ok := dreamerIndex(c.dreamer)
+	return i, ok && gameState.rooms[0].characters[i].player == c.name
+}
+
+// state is the game as seen by c
+func (s *Server) state(c *netClient) *netState {
+	gs := gameState
+	st := &netState{
+		Time: gs.elapsed.Seconds(),
+		Hunter: netHunter{
+			Active: gs.hunterActive,
+			Level:  gs.hunterLevel,
+			HP:     gs.hunterHP,
+			MaxHP:  gs.hunterMaxHP,
+		},
+		Doors: []netDoor{{Name: "host", Level: gs.doorLevel, HP: gs.doorHP, MaxHP: gs.doorMaxHP}},
+		Over:  gs.gameOver,
+		Won:   gs.gameWon,
+	}
+	for _, char := range gs.rooms[0].characters {
+		st.Doors = append(st.Doors, netDoor{Name: char.name, Player: char.player, Level: char.doorLevel, HP: char.doorHP, MaxHP: char.doorMaxHP})
+	}
+	if gs.hunterActive {
+		st.Hunter.Target = st.Doors[gs.hunterPos].Name
+	}
+
+	if i, ok := s.seat(c); ok {
+		char := &gs.rooms[0].characters[i]
+		you := &netPlayer{
+			Dreamer:   char.name,
+			Coins:     float64(char.coins),
+			CoinsPerS: float64(int(1) << uint(char.bedLevel-1)),
+			BedLevel:  char.bedLevel,
+			Guns:      len(char.guns),
+			Shop:      []netItem{},
+		}
+		for _, item := range RemoteShop(char) {
+			you.Shop = append(you.Shop, netItem{
+				ID:          item.itemType,
+				Name:        item.name,
+				Level:       item.currentLevel,
+				Cost:        float64(item.costCoins),
+				Description: item.description,
+			})
+		}
+		st.You = you
+	}
+	return st
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// syncUntil runs Sync until done reports true, failing after a while
+func syncUntil(t *testing.T, s *Server, what string, done func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(5 * time.Second); !done(); time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting until %s", what)
+		}
+		s.Sync(nil)
+	}
+}
+
+func TestServerFloodDoesNotBlockOthers(t *testing.T) {
+	SetDifficulty(difficultyPresets["normal"])
+	InitGameWithSeed(1)
+	s, err := Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	flooder, err := net.Dial("tcp", s.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer flooder.Close()
+	go func() {
+		line := []byte(`{"type":"flood"}` + "\n")
+		for {
+			if _, err := flooder.Write(line); err != nil {
+				return
+			}
+		}
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	player, err := net.Dial("tcp", s.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := player.Write([]byte(`{"type":"join","name":"Ann"}` + "\n")); err != nil {
+		t.Fatal(err)
+	}
+	seated := func() bool {
+		for _, char := range gameState.rooms[0].characters {
+			if char.player == "Ann" {
+				return true
+			}
+		}
+		return false
+	}
+	// By now the flooder has far more than a tick's worth of messages
+	// queued, yet Ann's join is handled in the very next tick
+	time.Sleep(100 * time.Millisecond)
+	s.Sync(nil)
+	if !seated() {
+		t.Fatal("Ann's join waited behind the flood")
+	}
+
+	player.Close()
+	syncUntil(t, s, "Ann leaves", func() bool { return !seated() && len(s.clients) == 1 })
+}
+
+func TestValidPlayerName(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"Ann", true},
+		{"Ñandú 夢", true},
+		{strings.Repeat("a", maxPlayerName), true},
+		{strings.Repeat("a", maxPlayerName+1), false},
+		{"bell\a", false},
+		{"\xff", false},
+	}
+	for _, tt := range tests {
+		if got := validPlayerName(tt.name); got != tt.want {
+			t.Errorf("validPlayerName(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
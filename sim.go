@@ -0,0 +1,227 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// simSample is one point on a game's resource curve
+type simSample struct {
+	Second    int     `json:"t"`
+	Coins     float64 `json:"coins"`
+	Diamonds  float64 `json:"diamonds"`
+	CoinsPerS float64 `json:"coins_per_s"`
+	DiamPerS  float64 `json:"diamonds_per_s"`
+	Guns      int     `json:"guns"`
+	DoorHP    int     `json:"door_hp"`
+	HunterHP  int     `json:"hunter_hp"`
+}
+
+// simResult is the outcome of one headless game
+type simResult struct {
+	Seed         int64       `json:"seed"`
+	Won          bool        `json:"won"`
+	Seconds      float64     `json:"seconds"`
+	HunterHPLeft int         `json:"hunter_hp_left"`
+	Samples      []simSample `json:"samples"`
+}
+
+// simConfig controls how a headless game is run
+type simConfig struct {
+	MaxSeconds  int // give up after this many game seconds
+	SampleEvery int // record a resource sample every N seconds, 0 for none
+	HunterLevel int // level of the hunter to beat
+}
+
+// simReport summarizes a batch of headless games
+type simReport struct {
+	Strategy        string      `json:"strategy"`
+	Difficulty      string      `json:"difficulty"`
+	Games           int         `json:"games"`
+	Wins            int         `json:"wins"`
+	WinRate         float64     `json:"win_rate"`
+	MedianTimeToWin float64     `json:"median_time_to_win"`
+	Runs            []simResult `json:"runs"`
+}
+
+// RunSim implements the sim subcommand and returns the process exit code
+func RunSim(args []string) int {
+	fs := flag.NewFlagSet("sim", flag.ContinueOnError)
+	games := fs.Int("games", 100, "number of games to run")
+	seed := fs.Int64("seed", 1, "seed of the first game; game i uses seed+i")
+	strategyName := fs.String("strategy", "greedy", "purchase strategy: "+strings.Join(StrategyNames(), ", "))
+	maxSeconds := fs.Int("max", 600, "give up on a game after this many game seconds")
+	level := fs.Int("level", 1, "level of the hunter to beat")
+	sampleEvery := fs.Int("sample", 1, "record resource curves every N game seconds")
+	format := fs.String("format", "csv", "output format: csv or json")
+	out := fs.String("out", "", "write output to this file instead of stdout")
+	difficultyName := fs.String("difficulty", "normal", "difficulty preset: easy, normal, nightmare or custom:key=value,...")
+	eventsPath := fs.String("events", "", "write every engine event to this JSON Lines file")
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	d, err := ParseDifficulty(*difficultyName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "sim: %v\n", err)
+		return 2
+	}
+	SetDifficulty(d)
+
+	strategy, ok := GetStrategy(*strategyName)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "sim: unknown strategy %q\n", *strategyName)
+		return 2
+	}
+	if *format != "csv" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "sim: unknown format %q\n", *format)
+		return 2
+	}
+	if *games < 1 || *maxSeconds < 1 || *sampleEvery < 1 || *level < 1 {
+		fmt.Fprintln(os.Stderr, "sim: -games, -max, -sample and -level must be positive")
+		return 2
+	}
+	config := simConfig{MaxSeconds: *maxSeconds, SampleEvery: *sampleEvery, HunterLevel: *level}
+
+	report := simReport{Strategy: strategy.Name(), Difficulty: d.Name, Games: *games}
+	winTimes := []float64{}
+	game := 0
+	stopEvents, err := startEventLog(*eventsPath, func(e *Event) { e.Game = intPtr(game) })
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "sim: %v\n", err)
+		return 1
+	}
+	for i := 0; i < *games; i++ {
+		game = i
+		result := RunSimGame(*seed+int64(i), strategy, config)
+		if result.Won {
+			report.Wins++
+			winTimes = append(winTimes, result.Seconds)
+		}
+		report.Runs = append(report.Runs, result)
+	}
+	if err := stopEvents(); err != nil {
+		fmt.Fprintf(os.Stderr, "sim: %v\n", err)
+		return 1
+	}
+	report.WinRate = float64(report.Wins) / float64(report.Games)
+	report.MedianTimeToWin = median(winTimes)
+
+	w := io.Writer(os.Stdout)
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "sim: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if *format == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(report)
+	} else {
+		err = writeSimCSV(w, report)
+		fmt.Fprintf(os.Stderr, "strategy=%s difficulty=%s games=%d wins=%d win_rate=%.3f median_time_to_win=%.1fs\n",
+			report.Strategy, report.Difficulty, report.Games, report.Wins, report.WinRate, report.MedianTimeToWin)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "sim: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// RunSimGame plays one game headless as fast as possible, stepping the
+// engine exactly like the TUI does.
+func RunSimGame(seed int64, strategy Strategy, config simConfig) simResult {
+	InitGameWithSeed(seed)
+	if config.HunterLevel > 0 {
+		gameState.hunterLevel = config.HunterLevel
+		gameState.hunterAttack = GetHunterAttack(config.HunterLevel)
+	}
+	result := simResult{Seed: seed}
+
+	limit := time.Duration(config.MaxSeconds) * time.Second
+	for gameState.elapsed < limit {
+		StepGame(nil)
+		if gameState.gameOver {
+			result.Won = gameState.gameWon
+			result.Seconds = gameState.elapsed.Seconds()
+			result.HunterHPLeft = gameState.hunterHP
+			return result
+		}
+
+		second := int(gameState.elapsed / time.Second)
+		if gameState.elapsed%time.Second == 0 && config.SampleEvery > 0 && second%config.SampleEvery == 0 {
+			result.Samples = append(result.Samples, takeSimSample(second))
+		}
+
+		RunStrategyStep(strategy, nil)
+	}
+
+	result.Seconds = float64(config.MaxSeconds)
+	result.HunterHPLeft = gameState.hunterHP
+	if !gameState.hunterActive {
+		result.HunterHPLeft = GetHunterHP(gameState.hunterLevel)
+	}
+	return result
+}
+
+func takeSimSample(second int) simSample {
+	return simSample{
+		Second:    second,
+		Coins:     float64(gameState.coins),
+		Diamonds:  float64(gameState.diamonds),
+		CoinsPerS: gameState.coinsPerS,
+		DiamPerS:  gameState.diamPerS,
+		Guns:      len(gameState.guns),
+		DoorHP:    gameState.doorHP,
+		HunterHP:  gameState.hunterHP,
+	}
+}
+
+func writeSimCSV(w io.Writer, report simReport) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"game", "seed", "t", "coins", "diamonds", "coins_per_s", "diamonds_per_s", "guns", "door_hp", "hunter_hp"})
+	for i, run := range report.Runs {
+		for _, s := range run.Samples {
+			cw.Write([]string{
+				strconv.Itoa(i),
+				strconv.FormatInt(run.Seed, 10),
+				strconv.Itoa(s.Second),
+				strconv.FormatFloat(s.Coins, 'f', -1, 64),
+				strconv.FormatFloat(s.Diamonds, 'f', -1, 64),
+				strconv.FormatFloat(s.CoinsPerS, 'f', -1, 64),
+				strconv.FormatFloat(s.DiamPerS, 'f', -1, 64),
+				strconv.Itoa(s.Guns),
+				strconv.Itoa(s.DoorHP),
+				strconv.Itoa(s.HunterHP),
+			})
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
@@ -0,0 +1,23 @@
+package main
+
+import "testing"
+
+func TestRunSimGameSeeds(t *testing.T) {
+	SetDifficulty(difficultyPresets["normal"])
+	config := simConfig{MaxSeconds: 600, HunterLevel: 1}
+
+	times := map[float64]bool{}
+	for seed := int64(1); seed <= 10; seed++ {
+		result := RunSimGame(seed, greedyStrategy{}, config)
+		if !result.Won {
+			t.Fatalf("seed %d: greedy lost on normal", seed)
+		}
+		if again := RunSimGame(seed, greedyStrategy{}, config); again.Seconds != result.Seconds {
+			t.Errorf("seed %d: won in %.1fs, then in %.1fs", seed, result.Seconds, again.Seconds)
+		}
+		times[result.Seconds] = true
+	}
+	if len(times) < 2 {
+		t.Errorf("seeds 1-10 all won in the same time: %v", times)
+	}
+}
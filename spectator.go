@@ -0,0 +1,288 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// spectatorEvents is how many recent engine events /events returns
+const spectatorEvents = 100
+
+// Spectator serves the live game as read-only JSON over HTTP:
+//
+//	GET /state          resources, items, guns, hunter and dreamer doors
+//	GET /events         the most recent engine events
+//	GET /events/stream  engine events as Server-Sent Events
+//
+// The engine goroutine hands it a fresh snapshot every tick through
+// Publish, so handlers never read the game while it changes.
+type Spectator struct {
+	srv *http.Server
+
+	mu     sync.Mutex
+	state  []byte   // newest /state body
+	recent [][]byte // newest events, oldest first
+	subs   map[chan spectatorEvent]bool
+}
+
+// spectatorEvent is one engine event ready to send
+type spectatorEvent struct {
+	kind string
+	data []byte
+}
+
+// spectator is the running spectator API, or nil when it is off
+var spectator *Spectator
+
+// spectatorState is the body of GET /state
+type spectatorState struct {
+	Time       float64              `json:"t"`
+	Difficulty string               `json:"difficulty"`
+	Over       bool                 `json:"over"`
+	Won        bool                 `json:"won"`
+	Resources  spectatorResources   `json:"resources"`
+	Items      spectatorItems       `json:"items"`
+	Guns       []spectatorGun       `json:"guns"`
+	Hunter     spectatorHunter      `json:"hunter"`
+	Dreamers   []spectatorDreamer   `json:"dreamers"`
+	AutoBuyers []spectatorAutoBuyer `json:"auto_buyers"`
+}
+
+type spectatorResources struct {
+	Coins        float64 `json:"coins"`
+	Diamonds     float64 `json:"diamonds"`
+	CoinsPerS    float64 `json:"coins_per_s"`
+	DiamondsPerS float64 `json:"diamonds_per_s"`
+}
+
+type spectatorItems struct {
+	BedLevel     int `json:"bed_level"`
+	DoorLevel    int `json:"door_level"`
+	DoorHP       int `json:"door_hp"`
+	DoorMaxHP    int `json:"door_max_hp"`
+	PlayboxLevel int `json:"playbox_level"`
+	Defense      int `json:"defense"`
+	MaxDefense   int `json:"max_defense"`
+}
+
+type spectatorGun struct {
+	Name        string  `json:"name"`
+	Damage      int     `json:"damage"`
+	AttackSpeed float64 `json:"attack_speed"`
+}
+
+type spectatorHunter struct {
+	Active       bool    `json:"active"`
+	Level        int     `json:"level"`
+	HP           int     `json:"hp"`
+	MaxHP        int     `json:"max_hp"`
+	Attack       int     `json:"attack"`
+	Target       string  `json:"target,omitempty"` // "you" or the dreamer whose door it attacks
+	NextSpawnInS float64 `json:"next_spawn_in_s,omitempty"`
+}
+
+type spectatorDreamer struct {
+	Name      string `json:"name"`
+	Player    string `json:"player,omitempty"` // networked player playing it
+	DoorLevel int    `json:"door_level"`
+	DoorHP    int    `json:"door_hp"`
+	DoorMaxHP int    `json:"door_max_hp"`
+}
+
+type spectatorAutoBuyer struct {
+	Kind     string `json:"kind"`
+	Enabled  bool   `json:"enabled"`
+	Reserve  int    `json:"reserve"`
+	IdleOnly bool   `json:"idle_only"`
+}
+
+// ListenSpectator starts the spectator API on addr, e.g. "localhost:8080",
+// and subscribes it to engine events
+func ListenSpectator(addr string) (*Spectator, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	sp := &Spectator{state: []byte("{}"), subs: map[chan spectatorEvent]bool{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/state", sp.serveState)
+	mux.HandleFunc("/events", sp.serveEvents)
+	mux.HandleFunc("/events/stream", sp.serveStream)
+	sp.srv = &http.Server{Handler: mux}
+	go sp.srv.Serve(ln)
+	AddEventListener(sp.handle)
+	return sp, nil
+}
+
+// Close stops the API and ends every event stream
+func (sp *Spectator) Close() error {
+	if sp == nil {
+		return nil
+	}
+	return sp.srv.Close()
+}
+
+// Publish snapshots the game for /state. It runs on the engine goroutine
+// once per tick.
+func (sp *Spectator) Publish() {
+	if sp == nil {
+		return
+	}
+	gs := gameState
+	st := spectatorState{
+		Time:       gs.elapsed.Seconds(),
+		Difficulty: gs.difficulty,
+		Over:       gs.gameOver,
+		Won:        gs.gameWon,
+		Resources: spectatorResources{
+			Coins:        float64(gs.coins),
+			Diamonds:     float64(gs.diamonds),
+			CoinsPerS:    gs.coinsPerS,
+			DiamondsPerS: gs.diamPerS,
+		},
+		Items: spectatorItems{
+			BedLevel:     gs.bedLevel,
+			DoorLevel:    gs.doorLevel,
+			DoorHP:       gs.doorHP,
+			DoorMaxHP:    gs.doorMaxHP,
+			PlayboxLevel: gs.playboxLevel,
+			Defense:      gs.playerDefense,
+			MaxDefense:   gs.playerMaxDefense,
+		},
+		Guns: []spectatorGun{},
+		Hunter: spectatorHunter{
+			Active: gs.hunterActive,
+			Level:  gs.hunterLevel,
+			HP:     gs.hunterHP,
+			MaxHP:  gs.hunterMaxHP,
+			Attack: gs.hunterAttack,
+		},
+		Dreamers:   []spectatorDreamer{},
+		AutoBuyers: []spectatorAutoBuyer{},
+	}
+	for _, gun := range gs.guns {
+		st.Guns = append(st.Guns, spectatorGun{Name: gun.name, Damage: gun.damage, AttackSpeed: gun.attackSpeed})
+	}
+	for _, char := range gs.rooms[0].characters {
+		st.Dreamers = append(st.Dreamers, spectatorDreamer{Name: char.name, Player: char.player, DoorLevel: char.doorLevel, DoorHP: char.doorHP, DoorMaxHP: char.doorMaxHP})
+	}
+	for _, buyer := range gs.autoBuyers {
+		if buyer.owned {
+			st.AutoBuyers = append(st.AutoBuyers, spectatorAutoBuyer{Kind: buyer.kind, Enabled: buyer.enabled, Reserve: buyer.reserve, IdleOnly: buyer.idleOnly})
+		}
+	}
+	switch {
+	case gs.hunterActive && gs.hunterPos == 0:
+		st.Hunter.Target = "you"
+	case gs.hunterActive:
+		st.Hunter.Target = gs.rooms[0].characters[gs.hunterPos-1].name
+	case !gs.gameOver:
+		st.Hunter.NextSpawnInS = float64(gs.hunterSpawnDelay - gs.hunterSpawnCounter)
+	}
+
+	data, _ := json.Marshal(st)
+	sp.mu.Lock()
+	sp.state = data
+	sp.mu.Unlock()
+}
+
+// handle keeps e for /events and sends it to every stream. A stream too
+// slow to keep up misses events rather than holding up the game.
+func (sp *Spectator) handle(e Event) {
+	data, _ := json.Marshal(e)
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	sp.recent = append(sp.recent, data)
+	if len(sp.recent) > spectatorEvents {
+		sp.recent = sp.recent[len(sp.recent)-spectatorEvents:]
+	}
+	for ch := range sp.subs {
+		select {
+		case ch <- spectatorEvent{kind: e.Type, data: data}:
+		default:
+		}
+	}
+}
+
+// readOnly turns away anything but GET and lets browser dashboards on
+// other origins read the API
+func readOnly(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "read-only API: only GET is allowed", http.StatusMethodNotAllowed)
+		return false
+	}
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	return true
+}
+
+func (sp *Spectator) serveState(w http.ResponseWriter, r *http.Request) {
+	if !readOnly(w, r) {
+		return
+	}
+	sp.mu.Lock()
+	data := sp.state
+	sp.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+	w.Write([]byte("\n"))
+}
+
+func (sp *Spectator) serveEvents(w http.ResponseWriter, r *http.Request) {
+	if !readOnly(w, r) {
+		return
+	}
+	sp.mu.Lock()
+	events := make([]json.RawMessage, len(sp.recent))
+	for i, data := range sp.recent {
+		events[i] = data
+	}
+	sp.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(events)
+}
+
+// serveStream sends engine events as they happen, named by event type,
+// with a comment line every 15 seconds to keep idle connections open
+func (sp *Spectator) serveStream(w http.ResponseWriter, r *http.Request) {
+	if !readOnly(w, r) {
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	ch := make(chan spectatorEvent, 64)
+	sp.mu.Lock()
+	sp.subs[ch] = true
+	sp.mu.Unlock()
+	defer func() {
+		sp.mu.Lock()
+		delete(sp.subs, ch)
+		sp.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(15 * time.Second)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e := <-ch:
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.kind, e.data)
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		}
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,352 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// RunServe implements the serve subcommand: host the game over SSH. Every
+// connection plays its own game in a play process of its own, saved in
+// the slot of the SSH user, or of their key when keys are checked.
+func RunServe(args []string) int {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s serve --ssh [flags]\n\nEvery SSH connection plays its own game, saved in the slot named after\nthe SSH user. With --authorized-keys the slot belongs to the key instead:\na key with a comment such as alice@laptop only logs in as alice, a key\nwithout one gets a slot named after its fingerprint. Connect with: ssh -p 2222 name@localhost\n\n", programName())
+		fs.PrintDefaults()
+	}
+	configPath := fs.String("config", DefaultConfigPath(), "config file path")
+	sshMode := fs.Bool("ssh", false, "serve the terminal UI over SSH")
+	addr := fs.String("addr", "localhost:2222", "address to listen on")
+	hostKeyPath := fs.String("host-key", "", "SSH host key, generated on first use (default ssh_host_key next to the config file)")
+	authorizedKeys := fs.String("authorized-keys", "", "only let in the keys listed in this authorized_keys file (default: anyone who can reach the address)")
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	if !*sshMode {
+		fmt.Fprintln(os.Stderr, "serve: nothing to serve; pass --ssh")
+		return exitUsage
+	}
+	if _, err := LoadConfig(*configPath); err != nil {
+		fmt.Fprintf(os.Stderr, "serve: %v\n", err)
+		return exitError
+	}
+	if *hostKeyPath == "" {
+		*hostKeyPath = filepath.Join(filepath.Dir(*configPath), "ssh_host_key")
+	}
+
+	config := &ssh.ServerConfig{NoClientAuth: *authorizedKeys == ""}
+	if *authorizedKeys != "" {
+		allowed, err := loadAuthorizedKeys(*authorizedKeys)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "serve: %v\n", err)
+			return exitError
+		}
+		config.PublicKeyCallback = func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			name, ok := allowed[string(key.Marshal())]
+			if !ok {
+				return nil, fmt.Errorf("unknown key for %s", conn.User())
+			}
+			slot := keySlot(key)
+			if name != "" {
+				if conn.User() != name {
+					return nil, fmt.Errorf("key of %s used to log in as %s", name, conn.User())
+				}
+				slot = sshSlot(name)
+			}
+			return &ssh.Permissions{Extensions: map[string]string{"slot": slot}}, nil
+		}
+	}
+	hostKey, err := loadHostKey(*hostKeyPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "serve: %v\n", err)
+		return exitError
+	}
+	config.AddHostKey(hostKey)
+
+	exe, err := os.Executable()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "serve: %v\n", err)
+		return exitError
+	}
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "serve: %v\n", err)
+		return exitError
+	}
+	defer ln.Close()
+
+	slots := &slotLocks{inUse: map[string]bool{}}
+	fmt.Printf("Serving over SSH on %s (host key %s)\n", ln.Addr(), ssh.FingerprintSHA256(hostKey.PublicKey()))
+	if config.NoClientAuth {
+		fmt.Println("No --authorized-keys: anyone who can reach the address can play in any slot.")
+	}
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "serve: %v\n", err)
+			return exitError
+		}
+		go serveSSH(conn, config, sshSession{exe: exe, configPath: *configPath, slots: slots})
+	}
+}
+
+// loadHostKey reads the host key at path, generating an ed25519 key there
+// if there is none yet
+func loadHostKey(path string) (ssh.Signer, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		_, key, genErr := ed25519.GenerateKey(rand.Reader)
+		if genErr != nil {
+			return nil, genErr
+		}
+		der, genErr := x509.MarshalPKCS8PrivateKey(key)
+		if genErr != nil {
+			return nil, genErr
+		}
+		data = pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return nil, err
+		}
+		err = os.WriteFile(path, data, 0o600)
+	}
+	if err != nil {
+		return nil, err
+	}
+	signer, err := ssh.ParsePrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return signer, nil
+}
+
+// loadAuthorizedKeys reads an OpenSSH authorized_keys file into a map from
+// wire-format keys to the player names in their comments
+func loadAuthorizedKeys(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	allowed := map[string]string{}
+	for len(strings.TrimSpace(string(data))) > 0 {
+		key, comment, _, rest, err := ssh.ParseAuthorizedKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		// A comment such as alice@laptop names the key's player alice
+		name, _, _ := strings.Cut(strings.TrimSpace(comment), "@")
+		allowed[string(key.Marshal())] = name
+		data = rest
+	}
+	if len(allowed) == 0 {
+		return nil, fmt.Errorf("%s: no keys", path)
+	}
+	return allowed, nil
+}
+
+// sshSlot is the save slot of an SSH user. Letters, digits and '-' are
+// kept and every other byte, '_' included, becomes _xx in hex, so no two
+// user names share a slot. The empty name gets "_".
+func sshSlot(user string) string {
+	if user == "" {
+		return "_"
+	}
+	var b strings.Builder
+	for i := 0; i < len(user); i++ {
+		c := user[i]
+		if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "_%02x", c)
+		}
+	}
+	return b.String()
+}
+
+// keySlot is the save slot of a key without a comment, named after its
+// fingerprint
+func keySlot(key ssh.PublicKey) string {
+	sum := sha256.Sum256(key.Marshal())
+	return "key_" + hex.EncodeToString(sum[:8])
+}
+
+// slotLocks are the save slots being played, so a slot only ever has one
+// game writing to it
+type slotLocks struct {
+	mu    sync.Mutex
+	inUse map[string]bool
+}
+
+// lock claims slot, or reports false if another session plays it
+func (l *slotLocks) lock(slot string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.inUse[slot] {
+		return false
+	}
+	l.inUse[slot] = true
+	return true
+}
+
+func (l *slotLocks) unlock(slot string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.inUse, slot)
+}
+
+// sshSession is how to start the game for one SSH session
+type sshSession struct {
+	exe        string // this program
+	configPath string
+	slots      *slotLocks
+}
+
+// serveSSH handles one SSH connection until it closes
+func serveSSH(conn net.Conn, config *ssh.ServerConfig, session sshSession) {
+	sconn, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	defer sconn.Close()
+	go ssh.DiscardRequests(reqs)
+
+	slot := sshSlot(sconn.User())
+	if sconn.Permissions != nil && sconn.Permissions.Extensions["slot"] != "" {
+		slot = sconn.Permissions.Extensions["slot"]
+	}
+	fmt.Printf("%s connected from %s (slot %s)\n", sconn.User(), sconn.RemoteAddr(), slot)
+	for newChannel := range chans {
+		if newChannel.ChannelType() != "session" {
+			newChannel.Reject(ssh.UnknownChannelType, "only sessions are supported")
+			continue
+		}
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			continue
+		}
+		go session.run(channel, requests, slot)
+	}
+	fmt.Printf("%s disconnected\n", sconn.User())
+}
+
+// run plays the game on one session channel: it waits for a terminal and
+// a shell request, then runs play on a pseudo-terminal of the same size
+func (s sshSession) run(channel ssh.Channel, requests <-chan *ssh.Request, slot string) {
+	var (
+		term       string
+		cols, rows = 80, 24
+		master     *os.File
+		once       sync.Once
+	)
+	done := func(status uint32) {
+		once.Do(func() {
+			channel.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{status}))
+			channel.Close()
+		})
+	}
+
+	for req := range requests {
+		switch req.Type {
+		case "pty-req":
+			var pty struct {
+				Term                 string
+				Cols, Rows, Wpx, Hpx uint32
+				Modes                string
+			}
+			ok := ssh.Unmarshal(req.Payload, &pty) == nil
+			if ok {
+				term, cols, rows = pty.Term, int(pty.Cols), int(pty.Rows)
+			}
+			req.Reply(ok, nil)
+		case "window-change":
+			var size struct{ Cols, Rows, Wpx, Hpx uint32 }
+			if ssh.Unmarshal(req.Payload, &size) == nil {
+				cols, rows = int(size.Cols), int(size.Rows)
+				if master != nil {
+					resizePTY(master, cols, rows)
+				}
+			}
+		case "shell":
+			if master != nil {
+				req.Reply(false, nil)
+				continue
+			}
+			if term == "" {
+				req.Reply(true, nil)
+				fmt.Fprint(channel.Stderr(), "The game needs a terminal: connect with ssh -t\r\n")
+				done(exitUsage)
+				continue
+			}
+
+			if !s.slots.lock(slot) {
+				req.Reply(true, nil)
+				fmt.Fprintf(channel.Stderr(), "Slot %s is already being played in another session\r\n", slot)
+				done(exitError)
+				continue
+			}
+
+			cmd := exec.Command(s.exe, "play", "--config="+s.configPath, "--slot="+slot)
+			cmd.Env = append(withoutEnv(os.Environ(), "TERM"), "TERM="+term)
+			var err error
+			master, err = startPTY(cmd, cols, rows)
+			if err != nil {
+				s.slots.unlock(slot)
+				req.Reply(true, nil)
+				fmt.Fprintf(channel.Stderr(), "could not start the game: %v\r\n", err)
+				done(exitError)
+				continue
+			}
+			req.Reply(true, nil)
+			go io.Copy(master, channel)
+			go func() {
+				io.Copy(channel, master)
+				status := uint32(exitOK)
+				if err := cmd.Wait(); err != nil {
+					status = exitError
+					var exitErr *exec.ExitError
+					if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+						status = uint32(exitErr.ExitCode())
+					}
+				}
+				s.slots.unlock(slot)
+				done(status)
+			}()
+		default:
+			if req.WantReply {
+				req.Reply(false, nil)
+			}
+		}
+	}
+
+	// The client went away: hang up the game, which saves and quits
+	if master != nil {
+		master.Close()
+	}
+}
+
+// withoutEnv drops the variable name from env
+func withoutEnv(env []string, name string) []string {
+	kept := []string{}
+	for _, kv := range env {
+		if !strings.HasPrefix(kv, name+"=") {
+			kept = append(kept, kv)
+		}
+	}
+	return kept
+}
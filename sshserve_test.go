@@ -0,0 +1,53 @@
+package main
+
+import "testing"
+
+func TestSSHSlot(t *testing.T) {
+	tests := []struct {
+		user string
+		want string
+	}{
+		{"", "_"},
+		{"ann-42", "ann-42"},
+		{"Ann", "Ann"},
+		{"a_b", "a_5fb"},
+		{"a.b", "a_2eb"},
+		{"../etc", "_2e_2e_2fetc"},
+		{"ñ", "_c3_b1"},
+	}
+	slots := map[string]string{}
+	for _, tt := range tests {
+		got := sshSlot(tt.user)
+		if got != tt.want {
+			t.Errorf("sshSlot(%q) = %q, want %q", tt.user, got, tt.want)
+		}
+		if err := validateSlot(got); err != nil {
+			t.Errorf("sshSlot(%q): %v", tt.user, err)
+		}
+		if other, taken := slots[got]; taken {
+			t.Errorf("%q and %q share slot %q", other, tt.user, got)
+		}
+		slots[got] = tt.user
+	}
+	// Users whose names look like escapes still get slots of their own
+	if a, b := sshSlot("a_2eb"), sshSlot("a.b"); a == b {
+		t.Errorf("a_2eb and a.b share slot %q", a)
+	}
+}
+
+func TestSlotLocks(t *testing.T) {
+	locks := &slotLocks{inUse: map[string]bool{}}
+	if !locks.lock("ann") {
+		t.Fatal("could not lock a free slot")
+	}
+	if locks.lock("ann") {
+		t.Error("locked a slot twice")
+	}
+	if !locks.lock("bob") {
+		t.Error("one locked slot blocked another")
+	}
+	locks.unlock("ann")
+	if !locks.lock("ann") {
+		t.Error("could not lock an unlocked slot")
+	}
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"sort"
+
+	"github.com/rivo/tview"
+)
+
+// ActionKind is what a strategy wants to do next
+type ActionKind int
+
+const (
+	ActionWait    ActionKind = iota
+	ActionBuy                // buy shop item Index in Category
+	ActionUpgrade            // upgrade Your Items entry Index
+	ActionSpawn              // spawn the hunter now
+
+	ActionAutoBuyerToggle  // turn auto-buyer Index on or off
+	ActionAutoBuyerReserve // cycle the reserve rule of auto-buyer Index
+	ActionAutoBuyerIdle    // toggle the only-between-hunters rule of auto-buyer Index
+	ActionSell             // sell Your Items entry Index
+)
+
+func (k ActionKind) String() string {
+	switch k {
+	case ActionBuy:
+		return "buy"
+	case ActionUpgrade:
+		return "upgrade"
+	case ActionSpawn:
+		return "spawn"
+	case ActionAutoBuyerToggle:
+		return "autobuy"
+	case ActionAutoBuyerReserve:
+		return "reserve"
+	case ActionAutoBuyerIdle:
+		return "idle"
+	case ActionSell:
+		return "sell"
+	}
+	return "wait"
+}
+
+// Action is a single move chosen by a strategy
+type Action struct {
+	Kind     ActionKind
+	Category int
+	Index    int
+	Count    int // batch size of a buy; 0 means 1, BuyMax as many as affordable
+}
+
+// Snapshot is a read-only copy of the game handed to strategies.
+// Changing it has no effect on the running game.
+type Snapshot struct {
+	State GameState
+	Shop  [][]Item // shop offerings, indexed by category
+}
+
+// Strategy plays the game through the same actions a human has.
+// Decide is called repeatedly; returning no actions or ActionWait
+// ends the current step.
+type Strategy interface {
+	Name() string
+	Decide(s Snapshot) []Action
+}
+
+var strategies = map[string]Strategy{
+	"greedy":  greedyStrategy{},
+	"economy": economyFirstStrategy{},
+	"guns":    gunsFirstStrategy{},
+}
+
+// GetStrategy looks up a built-in strategy by name
+func GetStrategy(name string) (Strategy, bool) {
+	s, ok := strategies[name]
+	return s, ok
+}
+
+// StrategyNames returns the built-in strategy names in sorted order
+func StrategyNames() []string {
+	names := []string{}
+	for name := range strategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// TakeSnapshot copies the current game state and shop offerings
+func TakeSnapshot() Snapshot {
+	state := *gameState
+	state.guns = append([]Gun{}, gameState.guns...)
+	state.itemsPanelItems = append([]string{}, gameState.itemsPanelItems...)
+	state.autoBuyers = append([]AutoBuyer{}, gameState.autoBuyers...)
+	state.rooms = make([]Room, len(gameState.rooms))
+	for i, room := range gameState.rooms {
+		room.items = append([]string{}, room.items...)
+		room.characters = append([]Character{}, room.characters...)
+		state.rooms[i] = room
+	}
+	state.rng = nil
+
+	shop := [][]Item{}
+	for category := 0; category < shopCategories; category++ {
+		shop = append(shop, GetAvailableItemsByCategory(category))
+	}
+	return Snapshot{State: state, Shop: shop}
+}
+
+// CanAfford reports whether item was affordable when the snapshot was taken
+func (s Snapshot) CanAfford(item Item) bool {
+	return s.State.coins >= item.costCoins && s.State.diamonds >= item.costDiamonds
+}
+
+// ApplyAction performs an action against the live game, records it if
+// a recording is running, and reports whether anything changed
+func ApplyAction(action Action, logPanel *tview.TextView) bool {
+	// Record how many a buy-max got, so replays do not depend on the plan
+	if action.Kind == ActionBuy && action.Count == BuyMax {
+		action.Count, _, _ = PlanPurchase(action.Index, action.Category, BuyMax)
+	}
+	changed := applyAction(action, logPanel)
+	if changed {
+		recordAction(action)
+	}
+	return changed
+}
+
+func applyAction(action Action, logPanel *tview.TextView) bool {
+	coins, diamonds := gameState.coins, gameState.diamonds
+	switch action.Kind {
+	case ActionBuy:
+		BuyItemsByCategory(action.Index, action.Category, action.Count, logPanel)
+	case ActionUpgrade:
+		// Upgrade through the Your Items panel without moving the player's cursor
+		selected := gameState.itemsPanelSelected
+		gameState.itemsPanelSelected = action.Index
+		UpgradeSelectedItem(logPanel)
+		gameState.itemsPanelSelected = selected
+	case ActionSpawn:
+		// The tutorial holds spawns until its current step is done
+		if gameState.hunterActive || gameState.gameOver || tutorial.HoldsSpawns() {
+			return false
+		}
+		SpawnHunter(logPanel)
+		return true
+	case ActionAutoBuyerToggle, ActionAutoBuyerReserve, ActionAutoBuyerIdle:
+		return changeAutoBuyer(action.Kind, action.Index, logPanel)
+	case ActionSell:
+		return SellItem(action.Index, logPanel)
+	default:
+		return false
+	}
+	return gameState.coins != coins || gameState.diamonds != diamonds
+}
+
+// RunStrategyStep lets strategy act until it waits or stops making progress
+func RunStrategyStep(strategy Strategy, logPanel *tview.TextView) {
+	for i := 0; i < 100 && !gameState.gameOver; i++ {
+		actions := strategy.Decide(TakeSnapshot())
+		changed := false
+		for _, action := range actions {
+			if ApplyAction(action, logPanel) {
+				changed = true
+			}
+		}
+		if !changed {
+			return
+		}
+	}
+}
+
+// greedyStrategy buys the cheapest affordable item in any category
+type greedyStrategy struct{}
+
+func (greedyStrategy) Name() string { return "greedy" }
+
+func (greedyStrategy) Decide(s Snapshot) []Action {
+	best := Action{Kind: ActionWait}
+	bestCost := BigNum(0)
+	for category, items := range s.Shop {
+		for i, item := range items {
+			if !s.CanAfford(item) {
+				continue
+			}
+			cost := item.costCoins + item.costDiamonds
+			if best.Kind == ActionWait || cost < bestCost {
+				best = Action{Kind: ActionBuy, Category: category, Index: i}
+				bestCost = cost
+			}
+		}
+	}
+	return []Action{best}
+}
+
+// economyFirstStrategy levels the Bed whenever it can and only buys guns
+// once the hunter has brought the door below half HP
+type economyFirstStrategy struct{}
+
+func (economyFirstStrategy) Name() string { return "economy" }
+
+func (economyFirstStrategy) Decide(s Snapshot) []Action {
+	for i, item := range s.Shop[0] {
+		if item.itemType == "bed" && s.CanAfford(item) {
+			return []Action{{Kind: ActionBuy, Category: 0, Index: i}}
+		}
+	}
+	if s.State.hunterActive && s.State.doorHP*2 < s.State.doorMaxHP {
+		return gunsFirstStrategy{}.Decide(s)
+	}
+	return []Action{{Kind: ActionWait}}
+}
+
+// gunsFirstStrategy buys the affordable gun with the best damage per
+// second per coin and never spends on anything else
+type gunsFirstStrategy struct{}
+
+func (gunsFirstStrategy) Name() string { return "guns" }
+
+func (gunsFirstStrategy) Decide(s Snapshot) []Action {
+	best := Action{Kind: ActionWait}
+	bestValue := 0.0
+	for i, item := range s.Shop[2] {
+		if !s.CanAfford(item) {
+			continue
+		}
+		value := float64(item.damage) * item.attackSpeed / float64(item.costCoins+item.costDiamonds)
+		if best.Kind == ActionWait || value > bestValue {
+			best = Action{Kind: ActionBuy, Category: 2, Index: i}
+			bestValue = value
+		}
+	}
+	return []Action{best}
+}
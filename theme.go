@@ -0,0 +1,136 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Theme recolors the screen. The UI keeps drawing with plain color names
+// such as "red" and "green"; a theme maps those names to the colors
+// actually shown.
+type Theme struct {
+	Name       string            `json:"name"`
+	Colors     map[string]string `json:"colors"`     // color name -> replacement, e.g. "green": "#0072b2"
+	Monochrome bool              `json:"monochrome"` // drop all colors
+	Symbols    bool              `json:"symbols"`    // also mark affordability and HP with symbols
+}
+
+// builtinThemes are always available. Colorblind uses the Okabe-Ito
+// palette, which stays distinguishable with the common color deficiencies.
+var builtinThemes = map[string]Theme{
+	"default": {Name: "default"},
+	"colorblind": {
+		Name: "colorblind",
+		Colors: map[string]string{
+			"green":  "#0072b2", // blue
+			"red":    "#d55e00", // vermillion
+			"yellow": "#f0e442",
+			"cyan":   "#56b4e9", // sky blue
+			"blue":   "#cc79a7", // reddish purple
+			"orange": "#e69f00",
+			"gold":   "#e69f00",
+		},
+		Symbols: true,
+	},
+	"mono": {Name: "mono", Monochrome: true, Symbols: true},
+}
+
+// ThemeNames returns the built-in theme names in sorted order
+func ThemeNames() []string {
+	names := []string{}
+	for name := range builtinThemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// themeDir holds custom themes as <name>.json next to the config file
+func themeDir(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), "themes")
+}
+
+// validateThemeName rejects names that are not safe as a file name
+func validateThemeName(name string) error {
+	if err := validateSlot(name); err != nil {
+		return fmt.Errorf("invalid theme name %q", name)
+	}
+	return nil
+}
+
+// LoadTheme returns a built-in theme or reads <name>.json from the themes
+// directory next to the config file
+func LoadTheme(configPath, name string) (*Theme, error) {
+	if t, ok := builtinThemes[name]; ok {
+		return &t, nil
+	}
+	if err := validateThemeName(name); err != nil {
+		return nil, err
+	}
+	t := &Theme{}
+	err := readJSONFile(filepath.Join(themeDir(configPath), name+".json"), t)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("unknown theme %q (built in: %v, or add %s.json to %s)", name, ThemeNames(), name, themeDir(configPath))
+	}
+	if err != nil {
+		return nil, err
+	}
+	t.Name = name
+	if _, err := t.palette(); err != nil {
+		return nil, fmt.Errorf("theme %s: %v", name, err)
+	}
+	return t, nil
+}
+
+// palette resolves the color replacements to tcell colors
+func (t *Theme) palette() (map[tcell.Color]tcell.Color, error) {
+	p := map[tcell.Color]tcell.Color{}
+	for from, to := range t.Colors {
+		fromColor, toColor := tcell.GetColor(from), tcell.GetColor(to)
+		if fromColor == tcell.ColorDefault {
+			return nil, fmt.Errorf("unknown color %q", from)
+		}
+		if toColor == tcell.ColorDefault {
+			return nil, fmt.Errorf("unknown color %q", to)
+		}
+		p[fromColor] = toColor
+	}
+	return p, nil
+}
+
+// showSymbols adds symbols next to color-coded states for themes that
+// cannot rely on hue alone
+var showSymbols bool
+
+// affordMark is the symbol shown before a shop item in symbol mode
+func affordMark(item Item, affordable bool) string {
+	if !showSymbols {
+		return ""
+	}
+	switch {
+	case item.currentLevel >= item.maxLevel && item.maxLevel < 999:
+		return glyph("★ ", "* ")
+	case affordable:
+		return glyph("✓ ", "+ ")
+	}
+	return glyph("✗ ", "x ")
+}
+
+// hpMark describes an HP level in symbol mode: healthy, low or critical
+func hpMark(current, max int) string {
+	if !showSymbols || max == 0 {
+		return ""
+	}
+	switch ratio := float64(current) / float64(max); {
+	case ratio > 0.5:
+		return " " + glyph("♥", "ok")
+	case ratio > 0.25:
+		return " !"
+	}
+	return " !!"
+}
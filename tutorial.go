@@ -0,0 +1,119 @@
+package main
+
+import "github.com/rivo/tview"
+
+// Panels a tutorial step can point the player at
+const (
+	PanelShop      = "shop"
+	PanelYourItems = "items"
+	PanelRoomItems = "room"
+)
+
+// tutorialStep is one instruction of the tutorial. The step is finished
+// once done reports true for the engine state.
+type tutorialStep struct {
+	panel string
+	text  func(km *Keymap) string
+	done  func(gs *GameState) bool
+	fight bool // the hunter may spawn during this step
+}
+
+var tutorialSteps = []tutorialStep{
+	{
+		panel: PanelShop,
+		text: func(km *Keymap) string {
+			return T("tutorial.bed",
+				km.hint(KeyCategoryPrev, KeyCategoryNext), km.hint(KeyShopUp, KeyShopDown), km.hint(KeyBuy))
+		},
+		done: func(gs *GameState) bool { return gs.bedLevel >= 2 },
+	},
+	{
+		panel: PanelYourItems,
+		text: func(km *Keymap) string {
+			return T("tutorial.door",
+				km.hint(KeyItemsDown, KeyItemsUp), km.hint(KeyUpgrade))
+		},
+		done: func(gs *GameState) bool { return gs.doorLevel >= 2 },
+	},
+	{
+		panel: PanelShop,
+		text: func(km *Keymap) string {
+			return T("tutorial.pistol",
+				km.hint(KeyCategoryPrev, KeyCategoryNext))
+		},
+		done: func(gs *GameState) bool { return len(gs.guns) > 0 },
+	},
+	{
+		panel: PanelRoomItems,
+		text: func(km *Keymap) string {
+			return T("tutorial.fight")
+		},
+		done:  func(gs *GameState) bool { return gs.gameOver && gs.gameWon },
+		fight: true,
+	},
+}
+
+// Tutorial walks a new player through the first purchases and a fight
+type Tutorial struct {
+	keymap  *Keymap
+	step    int
+	started bool
+}
+
+// tutorial is the running tutorial, or nil
+var tutorial *Tutorial
+
+// StartTutorial begins a tutorial game on Easy. Tutorial games are not
+// recorded: the spawn gating is not part of the engine's replay inputs.
+func StartTutorial(seed int64, keymap *Keymap) {
+	SetDifficulty(difficultyPresets["easy"])
+	recording = nil
+	InitGameWithSeed(seed)
+	tutorial = &Tutorial{keymap: keymap}
+}
+
+// HoldsSpawns reports whether hunter spawning is paused until the
+// current step is done
+func (t *Tutorial) HoldsSpawns() bool {
+	return t != nil && !t.Finished() && !tutorialSteps[t.step].fight
+}
+
+// Finished reports whether every step is done
+func (t *Tutorial) Finished() bool {
+	return t.step >= len(tutorialSteps)
+}
+
+// Panel names the panel the current step is about, or ""
+func (t *Tutorial) Panel() string {
+	if t == nil || t.Finished() {
+		return ""
+	}
+	return tutorialSteps[t.step].panel
+}
+
+// Text is the current instruction
+func (t *Tutorial) Text() string {
+	if t.Finished() {
+		return T("tutorial.complete")
+	}
+	return T("tutorial.step", t.step+1, len(tutorialSteps), tutorialSteps[t.step].text(t.keymap))
+}
+
+// Update moves past finished steps, announcing each new one in the log.
+// The hunter is sent in as soon as the fight step begins.
+func (t *Tutorial) Update(logPanel *tview.TextView) {
+	if t == nil {
+		return
+	}
+	if !t.started {
+		t.started = true
+		AddLog(logPanel, LogSystem, "[cyan]"+tview.Escape(t.Text())+"[white]")
+	}
+	for !t.Finished() && tutorialSteps[t.step].done(gameState) {
+		t.step++
+		AddLog(logPanel, LogSystem, "[cyan]"+tview.Escape(t.Text())+"[white]")
+		if !t.Finished() && tutorialSteps[t.step].fight {
+			SpawnHunter(logPanel)
+		}
+	}
+}
@@ -2,74 +2,238 @@ package main
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/rivo/tview"
 )
 
-func UpdateLogPanel(panelX *tview.TextView) {
-	// Don't clear, just update at the top by reading existing content
+// asciiMode replaces box-drawing and emoji glyphs with plain ASCII
+var asciiMode bool
+
+// glyph picks the fancy or the plain ASCII form of a symbol
+func glyph(fancy, plain string) string {
+	if asciiMode {
+		return plain
+	}
+	return fancy
+}
 
-	// Get current text and find where logs start
-	currentText := panelX.GetText(false)
-	logsStart := 0
+// logFollow keeps the log panel scrolled to the newest entry. It is
+// turned off while the player scrolls back.
+var logFollow = true
 
-	// Clear and write new content
-	panelX.Clear()
+// logDrawnVersion is the log buffer version last drawn to the panel
+var logDrawnVersion = -1
 
-	// Restore existing logs
-	if logsStart >= 0 && logsStart < len(currentText) {
-		fmt.Fprint(panelX, currentText[logsStart:])
+func UpdateLogPanel(panelX *tview.TextView) {
+	// Only redraw when the log or its filter changed
+	version := gameLog.Version()
+	if version == logDrawnVersion {
+		return
+	}
+	logDrawnVersion = version
+
+	panelX.SetTitle(gameLog.Title())
+	row, _ := panelX.GetScrollOffset()
+	panelX.SetText(gameLog.Render())
+	if logFollow {
+		panelX.ScrollToEnd()
+	} else {
+		panelX.ScrollTo(row, 0)
 	}
 }
 
-func UpdateItemsPanel(panel *tview.TextView) {
+// ScrollLogPanel scrolls the log by lines; scrolling to the bottom
+// resumes following new entries
+func ScrollLogPanel(panel *tview.TextView, lines int) {
+	row, _ := panel.GetScrollOffset()
+	row += lines
+	if row < 0 {
+		row = 0
+	}
+	_, _, _, height := panel.GetInnerRect()
+	total := strings.Count(panel.GetText(false), "\n")
+	if row >= total-height {
+		logFollow = true
+		panel.ScrollToEnd()
+		return
+	}
+	logFollow = false
+	panel.ScrollTo(row, 0)
+}
+
+func UpdateItemsPanel(panel *tview.TextView, keymap *Keymap) {
 	panel.Clear()
 
 	gs := GetGameState()
 	if gs.gameOver {
-		fmt.Fprintf(panel, "[red]GAME OVER[white]\n\n")
+		fmt.Fprintf(panel, "[red]%s[white]\n\n", T("ui.game_over"))
 	}
 
-	fmt.Fprintf(panel, "[gray](s/w: move, u: upgrade)[white]\n\n")
+	fmt.Fprintf(panel, "[gray]%s[white]\n\n", keymap.ItemsHint())
 
-	// Display items list with selection
+	// Display items list with selection; each row is a clickable region
 	for i, itemName := range gs.itemsPanelItems {
 		if i == gs.itemsPanelSelected {
-			fmt.Fprintf(panel, "[black:white]%s[white:-]\n", itemName)
+			fmt.Fprintf(panel, "[\"%s\"][black:white]%s[white:-][\"\"]\n", regionID(regionOwned, i), itemName)
+			// Details of the selected item: what selling it would give back
+			if coins, diamonds, ok := SellValue(i); ok {
+				fmt.Fprintf(panel, "  [gray]%s[white]\n", T("ui.sell_value", costString(coins, diamonds), FormatDecimal(difficulty.SellRefund*100, 0)))
+			}
 		} else {
-			fmt.Fprintf(panel, "%s\n", itemName)
+			fmt.Fprintf(panel, "[\"%s\"]%s[\"\"]\n", regionID(regionOwned, i), itemName)
 		}
 	}
 }
 
-func UpdateShopPanel(panel *tview.TextView, selectedItem int, category int) {
+// buyCounts are the batch sizes the shop cycles through
+var buyCounts = []int{1, 10, BuyMax}
+
+// buyCountName labels a batch size, e.g. ×10
+func buyCountName(count int) string {
+	if count == BuyMax {
+		return T("ui.buy_max")
+	}
+	return glyph("×", "x") + strconv.Itoa(count)
+}
+
+// costString formats a price in coins and diamonds
+func costString(coins, diamonds BigNum) string {
+	switch {
+	case coins > 0 && diamonds > 0:
+		return T("ui.cost_both", coins, diamonds)
+	case coins > 0:
+		return T("ui.cost_coins", coins)
+	case diamonds > 0:
+		return T("ui.cost_diamonds", diamonds)
+	}
+	return T("ui.cost_coins", coins)
+}
+
+// Shop sort and filter modes
+const (
+	ShopSortNone       = "default"
+	ShopSortCost       = "cost"
+	ShopSortDPS        = "dps"
+	ShopSortAffordable = "affordable"
+)
+
+// shopSorts are the shop sort modes in the order the sort key cycles
+var shopSorts = []string{ShopSortNone, ShopSortCost, ShopSortDPS, ShopSortAffordable}
+
+// ShopView returns the indices of a category's items in the order the shop
+// shows them: cheapest first, best damage per second per coin first (items
+// that deal no damage go last, cheapest first; diamonds are a separate
+// currency and do not count), or only those whose batch of buyCount is
+// affordable
+func ShopView(items []Item, category, buyCount int, sortMode string) []int {
+	view := []int{}
+	for i := range items {
+		if sortMode == ShopSortAffordable && !CanAffordBatch(i, category, buyCount) {
+			continue
+		}
+		view = append(view, i)
+	}
+	cost := func(i int) BigNum { return items[i].costCoins + items[i].costDiamonds }
+	dps := func(i int) float64 {
+		if items[i].costCoins == 0 {
+			return 0
+		}
+		return float64(items[i].damage) * items[i].attackSpeed / float64(items[i].costCoins)
+	}
+	switch sortMode {
+	case ShopSortCost:
+		sort.SliceStable(view, func(a, b int) bool { return cost(view[a]) < cost(view[b]) })
+	case ShopSortDPS:
+		sort.SliceStable(view, func(a, b int) bool {
+			if dps(view[a]) != dps(view[b]) {
+				return dps(view[a]) > dps(view[b])
+			}
+			return cost(view[a]) < cost(view[b])
+		})
+	}
+	return view
+}
+
+// shopSortName labels a sort mode
+func shopSortName(mode string) string {
+	return T("sort." + mode)
+}
+
+func UpdateShopPanel(panel *tview.TextView, keymap *Keymap, selectedItem int, category int, buyCount int, sortMode string, showAdvisor bool) {
 	panel.Clear()
 
-	categoryNames := []string{"COINS", "DIAMONDS", "GUNS"}
 	items := GetAvailableItemsByCategory(category)
 
 	// Show category tabs
-	fmt.Fprintf(panel, "[gray](←/→: category, ↑/↓: item, I: buy)[white]\n\n")
-	for i, name := range categoryNames {
+	fmt.Fprintf(panel, "[gray]%s[white]\n\n", keymap.ShopHint())
+	for i := 0; i < shopCategories; i++ {
+		name := categoryName(i)
 		if i == category {
-			fmt.Fprintf(panel, "[black:white]%s[white:-] ", name)
+			fmt.Fprintf(panel, "[\"%s\"][black:white]%s[white:-][\"\"] ", regionID(regionTab, i), name)
+		} else {
+			fmt.Fprintf(panel, "[\"%s\"][gray]%s[white][\"\"] ", regionID(regionTab, i), name)
+		}
+	}
+	fmt.Fprintf(panel, "\n[gray]%s[white] ", T("ui.buy"))
+	for i, count := range buyCounts {
+		if count == buyCount {
+			fmt.Fprintf(panel, "[\"%s\"][black:white]%s[white:-][\"\"] ", regionID(regionBuyCount, i), buyCountName(count))
+		} else {
+			fmt.Fprintf(panel, "[\"%s\"][gray]%s[white][\"\"] ", regionID(regionBuyCount, i), buyCountName(count))
+		}
+	}
+	fmt.Fprintf(panel, "\n[gray]%s[white] ", T("ui.sort"))
+	for i, mode := range shopSorts {
+		if mode == sortMode {
+			fmt.Fprintf(panel, "[\"%s\"][black:white]%s[white:-][\"\"] ", regionID(regionSort, i), shopSortName(mode))
 		} else {
-			fmt.Fprintf(panel, "[gray]%s[white] ", name)
+			fmt.Fprintf(panel, "[\"%s\"][gray]%s[white][\"\"] ", regionID(regionSort, i), shopSortName(mode))
 		}
 	}
 	fmt.Fprintf(panel, "\n\n")
 
-	for i, item := range items {
+	// Advisor recommendation
+	if showAdvisor {
+		if advice, ok := GetAdvice(TakeSnapshot()); ok {
+			fmt.Fprintf(panel, "[yellow]%s[white] %s %s %s\n  %s\n\n", T("ui.advisor"), categoryName(advice.Category), glyph("›", ">"), itemName(advice.Item.name), advice.Reason)
+		} else {
+			fmt.Fprintf(panel, "[yellow]%s[white] %s\n\n", T("ui.advisor"), T("ui.advisor_save"))
+		}
+	}
+
+	view := ShopView(items, category, buyCount, sortMode)
+	if len(view) == 0 {
+		fmt.Fprintf(panel, "[gray]%s[white]\n", T("ui.nothing_affordable"))
+	}
+	for _, i := range view {
+		item := items[i]
+		affordable := CanAffordBatch(i, category, buyCount)
 		color := GetItemColor(item)
+		if color != "[blue]" && !affordable {
+			color = "[red]"
+		}
+
+		// Build cost string; batches show their total along the price curve
+		costStr := costString(item.costCoins, item.costDiamonds)
+		coins, diamonds := item.costCoins, item.costDiamonds
+		if buyCount != 1 {
+			if n, batchCoins, batchDiamonds := PlanPurchase(i, category, buyCount); n > 0 {
+				coins, diamonds = batchCoins, batchDiamonds
+				costStr = glyph("×", "x") + strconv.Itoa(n) + " " + costString(coins, diamonds)
+			}
+		}
 
-		// Build cost string
-		costStr := ""
-		if item.costCoins > 0 && item.costDiamonds > 0 {
-			costStr = fmt.Sprintf("%dc+%dd", item.costCoins, item.costDiamonds)
-		} else if item.costCoins > 0 {
-			costStr = fmt.Sprintf("%dc", item.costCoins)
-		} else if item.costDiamonds > 0 {
-			costStr = fmt.Sprintf("%dd", item.costDiamonds)
+		// How long until the current income pays for it
+		eta := ""
+		if color == "[red]" {
+			if seconds, ok := AffordETA(coins, diamonds); ok {
+				eta = " [gray]" + glyph("·", "-") + " " + Tn("ui.eta", seconds, FormatDecimal(seconds, 0)) + "[white]"
+			} else {
+				eta = " [gray]" + glyph("·", "-") + " " + T("ui.eta_never") + "[white]"
+			}
 		}
 
 		// Build level string
@@ -81,65 +245,180 @@ func UpdateShopPanel(panel *tview.TextView, selectedItem int, category int) {
 		}
 
 		// Highlight selected item with background
+		region := regionID(regionShop, i)
 		if i == selectedItem {
-			fmt.Fprintf(panel, "[black:white]%s%s(%s/%s)[white:-]\n", color, item.name, costStr, lvlStr)
+			fmt.Fprintf(panel, "[\"%s\"][black:white]%s%s%s(%s/%s)[white:-][\"\"]\n", region, color, affordMark(item, affordable), itemName(item.name), costStr, lvlStr)
 		} else {
-			fmt.Fprintf(panel, "%s%s(%s/%s)[white]\n", color, item.name, costStr, lvlStr)
+			fmt.Fprintf(panel, "[\"%s\"]%s%s%s(%s/%s)[white][\"\"]\n", region, color, affordMark(item, affordable), itemName(item.name), costStr, lvlStr)
 		}
 
 		// Show description
-		fmt.Fprintf(panel, "  %s\n", item.description)
+		fmt.Fprintf(panel, "[\"%s\"]  %s%s[\"\"]\n", region, item.description, eta)
 	}
 }
 
+// shopCategories is the number of shop categories: coins, diamonds, guns
+// and auto-buyers
+const shopCategories = 4
+
+// categoryName is the label of shop category i
+func categoryName(i int) string {
+	return T(fmt.Sprintf("category.%d", i))
+}
+
+// difficultyName is the display name of a difficulty preset
+func difficultyName(name string) string {
+	if _, ok := localeEN.Messages["difficulty."+name]; !ok {
+		return name
+	}
+	return T("difficulty." + name)
+}
+
+// Clickable regions of the shop and Your Items panels
+const (
+	regionTab      = "tab"
+	regionShop     = "shop"
+	regionOwned    = "owned"
+	regionBuyCount = "count"
+	regionSort     = "sort"
+)
+
+// regionID names a clickable row, e.g. "shop-2"
+func regionID(kind string, index int) string {
+	return fmt.Sprintf("%s-%d", kind, index)
+}
+
+// parseRegionID splits a region ID made by regionID
+func parseRegionID(id string) (kind string, index int, ok bool) {
+	i := strings.LastIndex(id, "-")
+	if i < 0 {
+		return "", 0, false
+	}
+	index, err := strconv.Atoi(id[i+1:])
+	if err != nil {
+		return "", 0, false
+	}
+	return id[:i], index, true
+}
+
 func UpdateRoomDefensePanel(panel *tview.TextView) {
 	panel.Clear()
 
 	gs := GetGameState()
 	room := gs.rooms[gs.currentRoom]
 
-	fmt.Fprintf(panel, "[yellow]DREAMERS[white]\n\n")
+	fmt.Fprintf(panel, "[yellow]%s[white]\n\n", T("ui.dreamers"))
 
 	// Show player first
 	playerBar := DrawHPBar(gs.playerDefense, gs.playerMaxDefense, 15)
-	fmt.Fprintf(panel, "[green]You[white] %s %d/%d\n", playerBar, gs.playerDefense, gs.playerMaxDefense)
+	fmt.Fprintf(panel, "[green]%s[white] %s %d/%d\n", T("ui.you"), playerBar, gs.playerDefense, gs.playerMaxDefense)
 	doorBar := DrawHPBar(gs.doorHP, gs.doorMaxHP, 15)
-	fmt.Fprintf(panel, "Door Lv%d %s %d/%d\n\n", gs.doorLevel, doorBar, gs.doorHP, gs.doorMaxHP)
+	fmt.Fprintf(panel, "%s %s %d/%d\n\n", T("ui.door_level", gs.doorLevel), doorBar, gs.doorHP, gs.doorMaxHP)
 
-	// Show AI characters
+	// Show AI characters and the networked players playing some of them
 	for _, char := range room.characters {
-		fmt.Fprintf(panel, "[cyan]%-8s[white] Door Lv%d %d/%d\n", char.name, char.doorLevel, char.doorHP, char.doorMaxHP)
+		fmt.Fprintf(panel, "[cyan]%-8s[white] %s %d/%d%s%s\n", char.name, T("ui.door_level", char.doorLevel), char.doorHP, char.doorMaxHP, hpMark(char.doorHP, char.doorMaxHP), playerTag(char))
 	}
 }
 
+// playerTag names the networked player playing a dreamer, if any
+func playerTag(char Character) string {
+	if !char.controlled() {
+		return ""
+	}
+	return " [green]" + tview.Escape(char.player) + "[white]"
+}
+
 func UpdateRoomItemsPanel(panel *tview.TextView) {
 	panel.Clear()
 
 	gs := GetGameState()
 
-	fmt.Fprintf(panel, "[yellow]ROOM ITEMS[white]\n\n")
+	fmt.Fprintf(panel, "[yellow]%s[white]\n\n", T("ui.room_items"))
 
 	// Show door HP
 	doorBar := DrawHPBar(gs.doorHP, gs.doorMaxHP, 20)
-	fmt.Fprintf(panel, "[cyan]Door:[white] %s %d/%d\n\n", doorBar, gs.doorHP, gs.doorMaxHP)
+	fmt.Fprintf(panel, "[cyan]%s[white] %s %d/%d\n\n", T("ui.door"), doorBar, gs.doorHP, gs.doorMaxHP)
 
 	if len(gs.rooms[gs.currentRoom].items) == 0 {
-		fmt.Fprintf(panel, "[gray]No items[white]\n")
+		fmt.Fprintf(panel, "[gray]%s[white]\n", T("ui.no_items"))
 	} else {
 		for _, item := range gs.rooms[gs.currentRoom].items {
-			fmt.Fprintf(panel, "• %s\n", item)
+			fmt.Fprintf(panel, "%s %s\n", glyph("•", "*"), item)
 		}
 	}
 
 	if gs.hunterActive {
-		fmt.Fprintf(panel, "\n[red]⚠ HUNTER LEVEL %d[white]\n", gs.hunterLevel)
-		fmt.Fprintf(panel, "Attack: %d every 3s\n", gs.hunterAttack)
+		fmt.Fprintf(panel, "\n[red]%s %s[white]\n", glyph("⚠", "!"), T("ui.hunter_level", gs.hunterLevel))
+		interval := difficulty.HunterAttackInterval.Seconds()
+		fmt.Fprintf(panel, "%s\n", Tn("ui.hunter_attack", interval, gs.hunterAttack, localizeDecimal(strconv.FormatFloat(interval, 'g', -1, 64))))
 
 		hunterBar := DrawHPBar(gs.hunterHP, gs.hunterMaxHP, 20)
-		fmt.Fprintf(panel, "[red]HP:[white] %s %d/%d\n", hunterBar, gs.hunterHP, gs.hunterMaxHP)
+		fmt.Fprintf(panel, "[red]%s[white] %s %d/%d\n", T("ui.hp"), hunterBar, gs.hunterHP, gs.hunterMaxHP)
 	}
 }
 
+// doorMark draws a door on the map as intact, damaged or broken
+func doorMark(hp, maxHP int) string {
+	switch {
+	case hp <= 0:
+		return "[red]" + glyph("░", ".") + "[white]"
+	case hp < maxHP:
+		return "[yellow]" + glyph("▒", "=") + "[white]"
+	}
+	return "[green]" + glyph("█", "#") + "[white]"
+}
+
+// mapRow draws row i of the map: a room's name, its door and the stretch
+// of corridor outside it, where the hunter stands if it is attacking that
+// door. Row 0 is your room, row i+1 is dreamer i.
+func mapRow(i int, name string, doorHP, doorMaxHP int) string {
+	gs := GetGameState()
+	if len([]rune(name)) > 7 {
+		name = string([]rune(name)[:6]) + glyph("…", ".")
+	}
+	door := doorMark(doorHP, doorMaxHP)
+	if effects.DoorFlash(i) {
+		door = "[:red]" + door + "[:-]"
+	}
+	hall := "  "
+	if gs.hunterActive && gs.hunterPos == i {
+		hall = "[red]" + glyph("☠", "H") + "[white] "
+	} else if frame, ok := effects.DeathFrame(i); ok {
+		hall = "[red]" + frame + "[white] "
+	}
+	wall := glyph("┃", "|")
+	return fmt.Sprintf("%-7s %s%s%s%s", tview.Escape(name), door, wall, hall, wall)
+}
+
+// UpdateMapPanel draws the dorm: every room off one corridor with its door,
+// the hunter outside the door it is attacking and your guns. Combat
+// effects, when turned on, flash guns and doors and show damage numbers.
+func UpdateMapPanel(panel *tview.TextView) {
+	panel.Clear()
+
+	gs := GetGameState()
+	room := gs.rooms[gs.currentRoom]
+	effects.prune()
+
+	guns := ""
+	for _, gun := range gs.guns {
+		if effects.Firing(gun.name) {
+			guns += "[yellow]" + glyph("✦", "*") + "[cyan]"
+		} else {
+			guns += glyph("⌐", "-")
+		}
+	}
+	fmt.Fprintf(panel, "[green]%s[white] [cyan]%s[white]%s\n",
+		mapRow(0, T("ui.you"), gs.doorHP, gs.doorMaxHP), guns, effects.DamageNumbers(0))
+	for i, char := range room.characters {
+		fmt.Fprintf(panel, "%s%s%s\n", mapRow(i+1, char.name, char.doorHP, char.doorMaxHP), playerTag(char), effects.DamageNumbers(i+1))
+	}
+
+	fmt.Fprintf(panel, "\n[gray]%s[white]\n", T("ui.map_legend",
+		glyph("█", "#"), glyph("▒", "="), glyph("░", "."), glyph("☠", "H"), glyph("⌐", "-")))
+}
+
 func findString(text string, search string) int {
 	for i := 0; i <= len(text)-len(search); i++ {
 		if text[i:i+len(search)] == search {
@@ -155,6 +434,7 @@ func UpdateResourcePanel(panel *tview.TextView) {
 
 	gs := GetGameState()
 
-	fmt.Fprintf(panel, "[gold]Coins: %d (+%.1f/s)[white]  [cyan]Diamonds: %d (+%.1f/s)[white]  [orange]Defense: %d[white]",
-		gs.coins, gs.coinsPerS, gs.diamonds, gs.diamPerS, gs.playerMaxDefense)
+	fmt.Fprintf(panel, "[gold]%s[white]  [cyan]%s[white]  [orange]%s[white]  [gray]%s[white]",
+		T("ui.coins", gs.coins, FormatNumber(gs.coinsPerS)), T("ui.diamonds", gs.diamonds, FormatNumber(gs.diamPerS)),
+		T("ui.defense", gs.playerMaxDefense), difficultyName(gs.difficulty))
 }